@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"programcion-backend/internal/seeder"
+	"programcion-backend/pkg/config"
+	"programcion-backend/pkg/db"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const migrateUsage = `uso: programacion-backend migrate <comando>
+
+  up [n]         aplica las migraciones pendientes (o sólo las n siguientes)
+  down [n]       revierte las últimas n migraciones (por defecto 1)
+  status         lista las migraciones y si están aplicadas
+  create <name>  crea el par up/down vacío en ` + db.MigrationsDir
+
+// runMigrate ejecuta el subcomando `migrate`. create no necesita base de datos.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+	cmd, rest := args[0], args[1:]
+	if cmd == "create" {
+		if len(rest) != 1 {
+			return fmt.Errorf("%s", migrateUsage)
+		}
+		up, down, err := db.CreateMigration(db.MigrationsDir, rest[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(up)
+		fmt.Println(down)
+		return nil
+	}
+
+	steps := 0
+	if len(rest) > 0 {
+		n, err := strconv.Atoi(rest[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of steps %q", rest[0])
+		}
+		steps = n
+	}
+	if err := db.InitDB(cfg.Database); err != nil {
+		return err
+	}
+	switch cmd {
+	case "up":
+		done, err := db.MigrateUp(steps)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("El esquema está al día")
+		}
+		return nil
+	case "down":
+		done, err := db.MigrateDown(steps)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("No hay migraciones aplicadas")
+		}
+		return nil
+	case "status":
+		statuses, err := db.MigrationStatuses()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSIÓN\tNOMBRE\tESTADO\tAPLICADA")
+		for _, st := range statuses {
+			state, at := "pendiente", ""
+			if st.AppliedAt != nil {
+				state, at = "aplicada", st.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if st.Modified {
+				state = "modificada"
+			}
+			if st.Missing {
+				state = "desconocida"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, at)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("%s", migrateUsage)
+}
+
+// ensureSchema se ejecuta antes de servir: con DB_MIGRATE_ON_START=true aplica
+// las migraciones pendientes y, en cualquier caso, se niega a arrancar si el
+// esquema queda por detrás del binario.
+func ensureSchema(cfg *config.Config) {
+	if cfg.Database.MigrateOnStart {
+		if _, err := db.MigrateUp(0); err != nil {
+			log.WithError(err).Fatal("No se pudieron aplicar las migraciones")
+		}
+	}
+	if err := db.CheckSchema(); err != nil {
+		log.WithError(err).Fatal("Esquema de base de datos desactualizado")
+	}
+}
+
+const seedUsage = `uso: programacion-backend seed <perfil> [opciones]
+
+perfiles:
+  admin-only  crea el administrador de ADMIN_EMAIL/ADMIN_PASSWORD
+  demo        admin, usuarios de ejemplo (contraseña pass123), edificios, aulas y reservas
+  load-test   miles de edificios, aulas, usuarios, clases y reservas sintéticas
+
+demo y load-test cargan datos ficticios con contraseñas conocidas: requieren
+--allow-demo o SEED_ALLOW_DEMO=true.
+
+opciones:`
+
+// runSeed ejecuta el subcomando `seed`. Exige el esquema al día.
+func runSeed(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), seedUsage)
+		fs.PrintDefaults()
+	}
+	lt := seeder.DefaultLoadTestOptions()
+	allowDemo := fs.Bool("allow-demo", cfg.Seed.AllowDemo, "permite los perfiles demo y load-test")
+	fs.IntVar(&lt.Buildings, "buildings", lt.Buildings, "load-test: edificios")
+	fs.IntVar(&lt.RoomsPerBuilding, "rooms-per-building", lt.RoomsPerBuilding, "load-test: aulas por edificio")
+	fs.IntVar(&lt.Professors, "professors", lt.Professors, "load-test: profesores")
+	fs.IntVar(&lt.Students, "students", lt.Students, "load-test: estudiantes")
+	fs.IntVar(&lt.Classes, "classes", lt.Classes, "load-test: clases")
+	fs.IntVar(&lt.StudentsPerClass, "students-per-class", lt.StudentsPerClass, "load-test: inscritos por clase")
+	fs.IntVar(&lt.Reservations, "reservations", lt.Reservations, "load-test: reservas")
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		return errors.New("seed profile is required")
+	}
+	profile := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if err := db.InitDB(cfg.Database); err != nil {
+		return err
+	}
+	if err := db.CheckSchema(); err != nil {
+		return err
+	}
+	return seeder.Run(profile, seeder.Options{AllowDemo: *allowDemo, Admin: cfg.Admin, LoadTest: lt})
+}
+
+const configUsage = `uso: programacion-backend config print
+
+  print  muestra la configuración efectiva en YAML, con los secretos ocultos`
+
+// runConfig ejecuta el subcomando `config`. Si la configuración es inválida la
+// imprime igual y devuelve la lista de errores.
+func runConfig(cfg *config.Config, cfgErr error, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return fmt.Errorf("%s", configUsage)
+	}
+	if cfg == nil {
+		return cfgErr
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		return err
+	}
+	return cfgErr
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var announcementService *services.AnnouncementService
+
+type announcementReq struct {
+	Title string `json:"title" binding:"required"`
+	Body  string `json:"body" binding:"required"`
+}
+
+// ListClassAnnouncements devuelve los avisos de la clase con el estado de
+// lectura del usuario. Visible para el equipo docente y los inscritos.
+func ListClassAnnouncements(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+	role, _ := c.Get("role")
+	if roleStr, _ := role.(string); roleStr != "ADMIN" {
+		member, err := classService.IsMember(uint(classID), uid)
+		if err != nil || !member {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
+			return
+		}
+	}
+	list, err := announcementService.ListForClass(uint(classID), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func CreateClassAnnouncement(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermAnnounce) {
+		return
+	}
+	var req announcementReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	a, err := announcementService.Post(uint(classID), uid, req.Title, req.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+func DeleteClassAnnouncement(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	annID, err := strconv.ParseUint(c.Param("announcement_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid announcement id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermAnnounce) {
+		return
+	}
+	a, err := announcementService.Get(uint(annID))
+	if err != nil || a.ClassID != uint(classID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "announcement not found"})
+		return
+	}
+	if err := announcementService.Delete(a.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement deleted"})
+}
+
+func MarkAnnouncementRead(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	a, err := announcementService.Get(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "announcement not found"})
+		return
+	}
+	member, err := classService.IsMember(a.ClassID, uid)
+	if err != nil || !member {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
+		return
+	}
+	if err := announcementService.MarkRead(a.ID, uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement marked as read"})
+}
+
+// ListMyAnnouncements devuelve los avisos de todas las clases del estudiante (?unread=true)
+func ListMyAnnouncements(c *gin.Context) {
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	list, err := announcementService.ListForUser(uid, c.Query("unread") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
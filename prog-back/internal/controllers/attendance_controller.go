@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var attendanceService *services.AttendanceService
+
+// sessionForManager carga la sesión de clase y verifica que el usuario pueda gestionarla
+func sessionForManager(c *gin.Context) (*models.Reservation, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	resv, err := attendanceService.ClassSession(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !canManageClass(c, *resv.ClassID, services.PermTakeAttendance) {
+		return nil, false
+	}
+	return resv, true
+}
+
+// GetAttendanceCode devuelve el código rotativo que el profesor proyecta en el aula
+func GetAttendanceCode(c *gin.Context) {
+	resv, ok := sessionForManager(c)
+	if !ok {
+		return
+	}
+	code, expires := attendanceService.CurrentCode(resv.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"reservation_id": resv.ID,
+		"code":           code,
+		"expires_at":     expires,
+		"qr_payload":     fmt.Sprintf("attendance:%d:%s", resv.ID, code),
+	})
+}
+
+type selfAttendanceReq struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func MarkOwnAttendance(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req selfAttendanceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	a, err := attendanceService.SelfCheckIn(uint(id64), uid, req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+func ListSessionAttendance(c *gin.Context) {
+	resv, ok := sessionForManager(c)
+	if !ok {
+		return
+	}
+	list, err := attendanceService.ListForSession(resv.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+type setAttendanceReq struct {
+	Status string `json:"status" binding:"required,oneof=PRESENT LATE ABSENT EXCUSED"`
+	Note   string `json:"note"`
+}
+
+func SetStudentAttendance(c *gin.Context) {
+	resv, ok := sessionForManager(c)
+	if !ok {
+		return
+	}
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid student id"})
+		return
+	}
+	var req setAttendanceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	a, err := attendanceService.SetStatus(resv.ID, uint(studentID), req.Status, req.Note, uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+func GetClassAttendanceReport(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermViewReports) {
+		return
+	}
+	report, err := attendanceService.ClassReport(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-var authService = services.NewAuthService()
+var authService *services.AuthService
 
 type registerReq struct {
 	Name     string `json:"name" binding:"required"`
@@ -59,6 +59,25 @@ func Login(c *gin.Context) {
 	})
 }
 
+type acceptInviteReq struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// AcceptInvite permite a una cuenta invitada elegir su contraseña
+func AcceptInvite(c *gin.Context) {
+	var req acceptInviteReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := authService.AcceptInvite(req.Token, req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "password_set"})
+}
+
 func ConfirmUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id64, err := strconv.ParseUint(idStr, 10, 32)
@@ -9,12 +9,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-var classService = services.NewClassService()
+var classService *services.ClassService
 
 type createClassReq struct {
 	Name        string `json:"name" binding:"required,min=3"`
 	Description string `json:"description"`
 	Subject     string `json:"subject" binding:"required,min=2"`
+	TermID      *uint  `json:"term_id"`
+	MaxStudents int    `json:"max_students" binding:"min=0"`
 }
 
 func CreateClass(c *gin.Context) {
@@ -31,7 +33,7 @@ func CreateClass(c *gin.Context) {
 	}
 	professorID := userID.(uint)
 
-	class, err := classService.CreateClass(req.Name, req.Description, req.Subject, professorID)
+	class, err := classService.CreateClass(req.Name, req.Description, req.Subject, professorID, req.TermID, req.MaxStudents)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -52,6 +54,9 @@ func ListClasses(c *gin.Context) {
 	roleStr, _ := role.(string)
 
 	professorIDParam := c.Query("professor_id")
+	// Filtro opcional por período académico
+	termID64, _ := strconv.ParseUint(c.Query("term_id"), 10, 32)
+	termID := uint(termID64)
 
 	var classes []interface{}
 
@@ -59,7 +64,7 @@ func ListClasses(c *gin.Context) {
 		// Si es profesor, mostrar solo sus clases (a menos que sea ADMIN y especifique otro profesor)
 		if professorIDParam != "" && roleStr == "ADMIN" {
 			pid, _ := strconv.ParseUint(professorIDParam, 10, 32)
-			result, err := classService.GetClassesByProfessor(uint(pid))
+			result, err := classService.GetClassesByProfessor(uint(pid), termID)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
@@ -68,7 +73,7 @@ func ListClasses(c *gin.Context) {
 				classes = append(classes, cls)
 			}
 		} else {
-			result, err := classService.GetClassesByProfessor(uid)
+			result, err := classService.GetClassesByProfessor(uid, termID)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
@@ -81,7 +86,7 @@ func ListClasses(c *gin.Context) {
 		// Admin puede ver todas o filtrar por profesor
 		if professorIDParam != "" {
 			pid, _ := strconv.ParseUint(professorIDParam, 10, 32)
-			result, err := classService.GetClassesByProfessor(uint(pid))
+			result, err := classService.GetClassesByProfessor(uint(pid), termID)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
@@ -90,7 +95,7 @@ func ListClasses(c *gin.Context) {
 				classes = append(classes, cls)
 			}
 		} else {
-			result, err := classService.GetAllClasses()
+			result, err := classService.GetAllClasses(termID)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
@@ -100,13 +105,23 @@ func ListClasses(c *gin.Context) {
 			}
 		}
 	} else if roleStr == "STUDENT" {
-		// Estudiante: ver clases donde está inscrito
-		result, err := classService.GetClassesByStudent(uid)
+		// Estudiante: ver clases donde está inscrito o es ayudante
+		result, err := classService.GetClassesByStudent(uid, termID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		for _, cls := range result {
+		staffed, err := classService.GetClassesByStaff(uid, termID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		seen := map[uint]bool{}
+		for _, cls := range append(result, staffed...) {
+			if seen[cls.ID] {
+				continue
+			}
+			seen[cls.ID] = true
 			classes = append(classes, cls)
 		}
 	}
@@ -135,6 +150,7 @@ type updateClassReq struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Subject     string `json:"subject"`
+	MaxStudents *int   `json:"max_students" binding:"omitempty,min=0"`
 }
 
 func UpdateClass(c *gin.Context) {
@@ -154,10 +170,10 @@ func UpdateClass(c *gin.Context) {
 	role, _ := c.Get("role")
 	roleStr, _ := role.(string)
 
-	// Verificar ownership si no es admin
+	// Verificar permisos en el equipo docente si no es admin
 	if roleStr != "ADMIN" {
-		isOwner, err := classService.IsOwner(uint(id), uid)
-		if err != nil || !isOwner {
+		allowed, err := classService.HasPermission(uint(id), uid, services.PermEditClass)
+		if err != nil || !allowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
 			return
 		}
@@ -169,7 +185,7 @@ func UpdateClass(c *gin.Context) {
 		return
 	}
 
-	class, err := classService.UpdateClass(uint(id), req.Name, req.Description, req.Subject)
+	class, err := classService.UpdateClass(uint(id), req.Name, req.Description, req.Subject, req.MaxStudents)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -195,10 +211,10 @@ func DeleteClass(c *gin.Context) {
 	role, _ := c.Get("role")
 	roleStr, _ := role.(string)
 
-	// Verificar ownership si no es admin
+	// Verificar permisos en el equipo docente si no es admin
 	if roleStr != "ADMIN" {
-		isOwner, err := classService.IsOwner(uint(id), uid)
-		if err != nil || !isOwner {
+		allowed, err := classService.HasPermission(uint(id), uid, services.PermDeleteClass)
+		if err != nil || !allowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
 			return
 		}
@@ -233,10 +249,10 @@ func AddStudent(c *gin.Context) {
 	role, _ := c.Get("role")
 	roleStr, _ := role.(string)
 
-	// Verificar ownership si no es admin
+	// Verificar permisos en el equipo docente si no es admin
 	if roleStr != "ADMIN" {
-		isOwner, err := classService.IsOwner(uint(classID), uid)
-		if err != nil || !isOwner {
+		allowed, err := classService.HasPermission(uint(classID), uid, services.PermManageRoster)
+		if err != nil || !allowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
 			return
 		}
@@ -248,15 +264,21 @@ func AddStudent(c *gin.Context) {
 		return
 	}
 
-	if err := classService.AddStudent(uint(classID), req.StudentID); err != nil {
+	status, err := classService.AddStudent(uint(classID), req.StudentID)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	message := "Student added to class"
+	if status == "WAITLISTED" {
+		message = "Class is full, student added to waitlist"
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "Student added to class",
+		"message":    message,
 		"class_id":   classID,
 		"student_id": req.StudentID,
+		"status":     status,
 	})
 }
 
@@ -284,21 +306,26 @@ func RemoveStudent(c *gin.Context) {
 	role, _ := c.Get("role")
 	roleStr, _ := role.(string)
 
-	// Verificar ownership si no es admin
+	// Verificar permisos en el equipo docente si no es admin
 	if roleStr != "ADMIN" {
-		isOwner, err := classService.IsOwner(uint(classID), uid)
-		if err != nil || !isOwner {
+		allowed, err := classService.HasPermission(uint(classID), uid, services.PermManageRoster)
+		if err != nil || !allowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
 			return
 		}
 	}
 
-	if err := classService.RemoveStudent(uint(classID), uint(studentID)); err != nil {
+	promoted, err := classService.RemoveStudent(uint(classID), uint(studentID))
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Student removed from class"})
+	resp := gin.H{"message": "Student removed from class"}
+	if len(promoted) > 0 {
+		resp["promoted_student_ids"] = promoted
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 func ListClassStudents(c *gin.Context) {
@@ -308,6 +335,9 @@ func ListClassStudents(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
 		return
 	}
+	if !canManageClass(c, uint(classID), services.PermViewReports) {
+		return
+	}
 
 	students, err := classService.GetClassStudents(uint(classID))
 	if err != nil {
@@ -317,3 +347,289 @@ func ListClassStudents(c *gin.Context) {
 
 	c.JSON(http.StatusOK, students)
 }
+
+// canManageClass responde 403 si el usuario no es admin ni tiene el permiso
+// indicado dentro del equipo docente de la clase
+func canManageClass(c *gin.Context, classID uint, perm string) bool {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return false
+	}
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	if roleStr == "ADMIN" {
+		return true
+	}
+	allowed, err := classService.HasPermission(classID, userID.(uint), perm)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized"})
+		return false
+	}
+	return true
+}
+
+type joinCodeReq struct {
+	RequiresApproval bool `json:"requires_approval"`
+}
+
+func GetJoinCode(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	js, err := classService.GetJoinSettings(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, js)
+}
+
+// RegenerateJoinCode genera un nuevo código de inscripción y lo habilita
+func RegenerateJoinCode(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	var req joinCodeReq
+	// El cuerpo es opcional
+	_ = c.ShouldBindJSON(&req)
+
+	js, err := classService.RegenerateJoinCode(uint(classID), req.RequiresApproval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, js)
+}
+
+func DisableJoinCode(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	if err := classService.DisableJoinCode(uint(classID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "disabled"})
+}
+
+type joinClassReq struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// JoinClass permite a un estudiante inscribirse con el código de la clase
+func JoinClass(c *gin.Context) {
+	var req joinClassReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	class, status, err := classService.JoinByCode(req.Code, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"class_id":   class.ID,
+		"class_name": class.Name,
+		"status":     status,
+	})
+}
+
+func ListEnrollmentRequests(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	students, err := classService.GetPendingStudents(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, students)
+}
+
+func ApproveEnrollmentRequest(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid student id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	status, err := classService.ApproveEnrollment(uint(classID), uint(studentID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Enrollment request approved", "status": status})
+}
+
+func RejectEnrollmentRequest(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid student id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	if err := classService.RejectEnrollment(uint(classID), uint(studentID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Enrollment request rejected"})
+}
+
+func ListClassWaitlist(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	students, err := classService.GetWaitlist(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, students)
+}
+
+func ListClassStaff(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermViewReports) {
+		return
+	}
+	staff, err := classService.GetStaff(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, staff)
+}
+
+type addStaffReq struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=CO_TEACHER TA"`
+}
+
+func AddClassStaff(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageStaff) {
+		return
+	}
+	var req addStaffReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	m, err := classService.AddStaff(uint(classID), req.UserID, req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+func RemoveClassStaff(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageStaff) {
+		return
+	}
+	if err := classService.RemoveStaff(uint(classID), uint(userID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Staff member removed from class"})
+}
+
+func ListDeletedClasses(c *gin.Context) {
+	classes, err := classService.ListDeletedClasses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, classes)
+}
+
+func RestoreClass(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := classService.RestoreClass(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "restored"})
+}
+
+// PurgeClass elimina definitivamente una clase previamente borrada
+func PurgeClass(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := classService.PurgeClass(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var classSessionService *services.ClassSessionService
+
+type classSessionReq struct {
+	RoomID    uint   `json:"room_id" binding:"required"`
+	Weekday   *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required"` // HH:MM
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+func ListClassSessions(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermViewReports) {
+		return
+	}
+	list, err := classSessionService.List(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func CreateClassSession(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageSchedule) {
+		return
+	}
+	var req classSessionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sess := &models.ClassSession{
+		ClassID:   uint(classID),
+		RoomID:    req.RoomID,
+		Weekday:   *req.Weekday,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	conflicts, err := classSessionService.Create(sess)
+	if respondPersonConflict(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(conflicts) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "time slot not available (overlap)", "conflicts": conflicts})
+		return
+	}
+	c.JSON(http.StatusCreated, sess)
+}
+
+func UpdateClassSession(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageSchedule) {
+		return
+	}
+	sess, err := classSessionService.Get(uint(classID), uint(sessionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var req classSessionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sess.RoomID = req.RoomID
+	sess.Room = nil
+	sess.Weekday = *req.Weekday
+	sess.StartTime = req.StartTime
+	sess.EndTime = req.EndTime
+
+	conflicts, err := classSessionService.Update(sess)
+	if respondPersonConflict(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(conflicts) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "time slot not available (overlap)", "conflicts": conflicts})
+		return
+	}
+	c.JSON(http.StatusOK, sess)
+}
+
+func DeleteClassSession(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageSchedule) {
+		return
+	}
+	if err := classSessionService.Delete(uint(classID), uint(sessionID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
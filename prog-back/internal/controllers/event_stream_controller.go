@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var eventStreamService = services.NewEventStreamService()
+
+// Comentario periódico para que proxies y navegadores no corten la conexión
+const streamHeartbeat = 25 * time.Second
+
+// StreamEvents envía por Server-Sent Events los cambios de reservas y aulas.
+// Filtros: ?building_id=, ?room_id=, ?mine=true. Para reanudar se usa la
+// cabecera Last-Event-ID (o ?last_event_id= en la primera conexión).
+func StreamEvents(c *gin.Context) {
+	var buildingID, roomID, lastID uint
+	for param, dst := range map[string]*uint{"building_id": &buildingID, "room_id": &roomID, "last_event_id": &lastID} {
+		if v := c.Query(param); v != "" {
+			id, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+				return
+			}
+			*dst = uint(id)
+		}
+	}
+	if v := c.GetHeader("Last-Event-ID"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		lastID = uint(id)
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+	roleI, _ := c.Get("role")
+	role, _ := roleI.(string)
+
+	filter, err := eventStreamService.NewFilter(uid, role, buildingID, roomID, c.Query("mine") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := eventStreamService.Subscribe(filter, lastID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// El id enviado es el punto seguro de reanudación, no el del evento
+	write := func(ev services.StreamEvent) {
+		fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", ev.Resume, ev.Event, ev.Data)
+	}
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	// Un evento que confirmó tarde puede llegar en el replay y también en vivo
+	replayed := make(map[uint]bool, len(sub.Replay))
+	for _, ev := range sub.Replay {
+		replayed[ev.ID] = true
+		write(ev)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case ev, ok := <-sub.Events:
+			if !ok {
+				// Cliente demasiado lento: al reconectar recupera lo perdido
+				return
+			}
+			if replayed[ev.ID] {
+				continue
+			}
+			write(ev)
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var examService = services.NewExamService()
+
+// examForStaff carga el examen y verifica que el usuario tenga el permiso en su clase
+func examForStaff(c *gin.Context, perm string) (*models.Exam, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	exam, err := examService.Get(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "exam not found"})
+		return nil, false
+	}
+	if !canManageClass(c, exam.ClassID, perm) {
+		return nil, false
+	}
+	return exam, true
+}
+
+func CreateExam(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageSchedule) {
+		return
+	}
+	var req services.ExamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	exam, err := examService.Schedule(uint(classID), uid, req)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, exam)
+}
+
+func ListClassExams(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermTakeAttendance) {
+		return
+	}
+	list, err := examService.ListByClass(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func GetExam(c *gin.Context) {
+	exam, ok := examForStaff(c, services.PermTakeAttendance)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, exam)
+}
+
+func CancelExam(c *gin.Context) {
+	exam, ok := examForStaff(c, services.PermManageSchedule)
+	if !ok {
+		return
+	}
+	if err := examService.Cancel(exam.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Exam cancelled"})
+}
+
+// GetExamSeating devuelve la lista de asientos de un aula (?format=csv para imprimir)
+func GetExamSeating(c *gin.Context) {
+	exam, ok := examForStaff(c, services.PermTakeAttendance)
+	if !ok {
+		return
+	}
+	roomID, err := strconv.ParseUint(c.Param("room_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
+		return
+	}
+	er, err := examService.SeatingList(exam.ID, uint(roomID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, er)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"exam-%d-room-%d-seating.csv\"", exam.ID, roomID))
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"seat", "student_id", "name", "email"})
+	for _, seat := range er.Seats {
+		name, email := "", ""
+		if seat.Student != nil {
+			name, email = seat.Student.Name, seat.Student.Email
+		}
+		_ = w.Write([]string{
+			strconv.Itoa(seat.SeatNumber),
+			strconv.FormatUint(uint64(seat.StudentID), 10),
+			name,
+			email,
+		})
+	}
+	w.Flush()
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+)
+
+// Init crea los servicios que dependen de la configuración. Debe llamarse
+// antes de registrar las rutas.
+func Init(cfg *config.Config) {
+	announcementService = services.NewAnnouncementService(cfg)
+	attendanceService = services.NewAttendanceService(cfg)
+	authService = services.NewAuthService(cfg)
+	classService = services.NewClassService(cfg)
+	classSessionService = services.NewClassSessionService(cfg)
+	notificationService = services.NewNotificationService(cfg)
+	publicReservationService = services.NewPublicReservationService(cfg)
+	reservationService = services.NewReservationService(cfg)
+	signageService = services.NewSignageService(cfg)
+	timetableService = services.NewTimetableService(cfg)
+	userService = services.NewUserService(cfg)
+	webhookService = services.NewWebhookService(cfg)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var notificationService *services.NotificationService
+
+// ListMyNotifications devuelve la bandeja de entrada in-app (?unread=true sólo no leídas)
+func ListMyNotifications(c *gin.Context) {
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	list, unread, err := notificationService.Inbox(uid, c.Query("unread") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread": unread, "notifications": list})
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	if err := notificationService.MarkRead(uint(id64), uid); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	if err := notificationService.MarkAllRead(uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
+
+func GetNotificationSettings(c *gin.Context) {
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	settings, err := notificationService.GetSettings(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func UpdateNotificationSettings(c *gin.Context) {
+	var req services.NotificationSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	settings, err := notificationService.UpdateSettings(uid, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
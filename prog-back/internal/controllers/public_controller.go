@@ -1,56 +1,104 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"programcion-backend/pkg/db"
+	"programcion-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-type PublicReservationResp struct {
-	ID                 uint   `json:"id"`
-	RoomID             uint   `json:"room_id"`
-	RoomName           string `json:"room_name"`
-	BuildingID         uint   `json:"building_id"`
-	BuildingName       string `json:"building_name"`
-	UserID             uint   `json:"user_id"`
-	UserName           string `json:"user_name"`
-	UserEmail          string `json:"user_email"`
-	StartTime          string `json:"start_time"`
-	EndTime            string `json:"end_time"`
-	Purpose            string `json:"purpose"`
-	EstimatedAttendees int    `json:"estimated_attendees"`
-	Status             string `json:"status"`
-}
+var publicReservationService *services.PublicReservationService
 
+// GetPublicReservations lista las reservas activas sin datos personales.
+// Filtros: from, to (RFC3339 o YYYY-MM-DD; por defecto los próximos 7 días),
+// building_id, room_id, fields, limit y cursor (next_cursor de la página anterior).
+// Responde con ETag y acepta If-None-Match.
 func GetPublicReservations(c *gin.Context) {
-	// Simple join query to gather reservation + room + building + user
-	rows, err := db.GetDB().Raw(`
-        SELECT r.id, r.room_id, rm.name as room_name, rm.building_id, b.name as building_name,
-               r.user_id, u.name as user_name, u.email as user_email,
-               r.start_time::text as start_time, r.end_time::text as end_time,
-               r.purpose, r.estimated_attendees, r.status
-        FROM reservations r
-        JOIN rooms rm ON rm.id = r.room_id
-        JOIN buildings b ON b.id = rm.building_id
-        LEFT JOIN users u ON u.id = r.user_id
-        WHERE r.status = 'ACTIVE'
-        ORDER BY r.start_time ASC
-    `).Rows()
+	var req services.PublicReservationRequest
+	if v := c.Query("from"); v != "" {
+		t, err := parseRangeParam(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		req.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := parseRangeParam(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+		// Una fecha sola incluye todo ese día
+		if len(v) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1)
+		}
+		req.To = &t
+	}
+	for param, dst := range map[string]*uint{"building_id": &req.BuildingID, "room_id": &req.RoomID} {
+		if v := c.Query(param); v != "" {
+			id, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+				return
+			}
+			*dst = uint(id)
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		req.Limit = n
+	}
+	if v := c.Query("fields"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			req.Fields = append(req.Fields, strings.TrimSpace(f))
+		}
+	}
+	req.Cursor = c.Query("cursor")
+
+	page, err := publicReservationService.List(req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidPublicQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	defer rows.Close()
-	var out []PublicReservationResp
-	for rows.Next() {
-		var r PublicReservationResp
-		if err := rows.Scan(&r.ID, &r.RoomID, &r.RoomName, &r.BuildingID, &r.BuildingName, &r.UserID, &r.UserName, &r.UserEmail, &r.StartTime, &r.EndTime, &r.Purpose, &r.EstimatedAttendees, &r.Status); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+	body, err := json.Marshal(page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=30")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatches compara If-None-Match (lista separada por comas o "*") con el ETag
+func etagMatches(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
 		}
-		out = append(out, r)
 	}
-	c.JSON(http.StatusOK, out)
+	return false
 }
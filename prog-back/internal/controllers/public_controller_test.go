@@ -0,0 +1,28 @@
+package controllers
+
+import "testing"
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `W/"abc123"`
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"sin encabezado", "", false},
+		{"igual", `W/"abc123"`, true},
+		{"fuerte contra débil", `"abc123"`, true},
+		{"comodín", "*", true},
+		{"lista con coincidencia", `"zzz", W/"abc123"`, true},
+		{"lista sin coincidencia", `"zzz", W/"yyy"`, false},
+		{"distinto", `W/"abc124"`, false},
+		{"sin comillas", "abc123", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.header, etag); got != tt.want {
+				t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.header, etag, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetNoShowReport(c *gin.Context) {
+	var from, to *time.Time
+	if f := c.Query("date_from"); f != "" {
+		if t, err := time.Parse(time.RFC3339, f); err == nil {
+			from = &t
+		}
+	}
+	if t := c.Query("date_to"); t != "" {
+		if tt, err := time.Parse(time.RFC3339, t); err == nil {
+			to = &tt
+		}
+	}
+	list, err := reservationService.NoShowReport(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
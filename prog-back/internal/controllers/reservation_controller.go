@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,7 +12,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-var reservationService = services.NewReservationService()
+var reservationService *services.ReservationService
+
+// respondPersonConflict responde 409 con las reservas en conflicto si el error
+// es un choque de personas
+func respondPersonConflict(c *gin.Context, err error) bool {
+	var pce *services.PersonConflictError
+	if !errors.As(err, &pce) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": pce.Conflicts})
+	return true
+}
 
 type createReservationReq struct {
 	RoomID             uint   `json:"room_id" binding:"required"`
@@ -20,23 +32,40 @@ type createReservationReq struct {
 	EndTime            string `json:"end_time" binding:"required"`
 	Purpose            string `json:"purpose"`
 	EstimatedAttendees int    `json:"estimated_attendees"`
+	Private            bool   `json:"private"`
 }
 
 func CreateReservation(c *gin.Context) {
+	resv, ok := bindReservation(c)
+	if !ok {
+		return
+	}
+	if err := reservationService.Create(resv); err != nil {
+		if respondPersonConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, resv)
+}
+
+// bindReservation parsea el cuerpo de creación de reserva y responde 400 si es inválido
+func bindReservation(c *gin.Context) (*models.Reservation, bool) {
 	var req createReservationReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		return nil, false
 	}
 	st, err := time.Parse(time.RFC3339, req.StartTime)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time format"})
-		return
+		return nil, false
 	}
 	et, err := time.Parse(time.RFC3339, req.EndTime)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time format"})
-		return
+		return nil, false
 	}
 
 	// get user from context
@@ -46,7 +75,7 @@ func CreateReservation(c *gin.Context) {
 		uid = v
 	}
 
-	resv := &models.Reservation{
+	return &models.Reservation{
 		RoomID:             req.RoomID,
 		UserID:             uid,
 		ClassID:            req.ClassID,
@@ -54,14 +83,61 @@ func CreateReservation(c *gin.Context) {
 		EndTime:            et,
 		Purpose:            req.Purpose,
 		EstimatedAttendees: req.EstimatedAttendees,
+		Private:            req.Private,
+	}, true
+}
+
+// CreateHold bloquea provisionalmente un horario durante el asistente de reserva
+func CreateHold(c *gin.Context) {
+	resv, ok := bindReservation(c)
+	if !ok {
+		return
 	}
-	if err := reservationService.Create(resv); err != nil {
+	if err := reservationService.PlaceHold(resv); err != nil {
+		if respondPersonConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, resv)
 }
 
+func ConfirmHold(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	resv, err := reservationService.ConfirmHold(uint(id64), uid)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resv)
+}
+
+func ReleaseHold(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	if err := reservationService.ReleaseHold(uint(id64), uid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "released"})
+}
+
 func ListReservations(c *gin.Context) {
 	// parse optional filters
 	var filter = make(map[string]interface{})
@@ -75,6 +151,11 @@ func ListReservations(c *gin.Context) {
 			filter["user_id"] = uint(id)
 		}
 	}
+	if term := c.Query("term_id"); term != "" {
+		if id, err := strconv.ParseUint(term, 10, 32); err == nil {
+			filter["term_id"] = uint(id)
+		}
+	}
 	var from, to *time.Time
 	if f := c.Query("date_from"); f != "" {
 		if t, err := time.Parse(time.RFC3339, f); err == nil {
@@ -114,3 +195,45 @@ func CancelReservation(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "cancelled"})
 }
+
+func CheckInReservation(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+
+	resv, err := reservationService.CheckIn(uint(id64), uid, roleStr == "ADMIN")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resv)
+}
+
+type qrCheckinReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CheckInByQR permite hacer check-in desde el móvil escaneando el QR del aula
+func CheckInByQR(c *gin.Context) {
+	var req qrCheckinReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	resv, err := reservationService.CheckInByRoomToken(req.Token, uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resv)
+}
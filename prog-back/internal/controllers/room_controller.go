@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -19,7 +20,7 @@ func CreateRoom(c *gin.Context) {
 		return
 	}
 	if err := roomService.Create(&r); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		roomError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, r)
@@ -55,7 +56,7 @@ func UpdateRoom(c *gin.Context) {
 	}
 	payload.ID = uint(id64)
 	if err := roomService.Update(&payload); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		roomError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, payload)
@@ -70,3 +71,26 @@ func DeleteRoom(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
+
+func RegenerateRoomCheckinToken(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	token, err := roomService.RegenerateCheckinToken(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room_id": id64, "checkin_token": token})
+}
+
+func roomError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidRoom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxRosterSize = 1 << 20 // 1 MB
+
+// ImportClassRoster inscribe estudiantes desde un CSV (email,name). Acepta el
+// archivo como multipart (campo "file") o como cuerpo text/csv.
+func ImportClassRoster(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermManageRoster) {
+		return
+	}
+	invite := c.Query("invite") == "true"
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRosterSize)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing file"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	results, err := classService.ImportRoster(uint(classID), body, invite)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	summary := map[string]int{}
+	for _, r := range results {
+		summary[r.Status]++
+	}
+	c.JSON(http.StatusOK, gin.H{"summary": summary, "rows": results})
+}
+
+// ExportClassRoster descarga el padrón (inscriptos y lista de espera) en CSV
+func ExportClassRoster(c *gin.Context) {
+	classID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid class id"})
+		return
+	}
+	if !canManageClass(c, uint(classID), services.PermViewReports) {
+		return
+	}
+	roster, err := classService.GetRoster(uint(classID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"class-%d-roster.csv\"", classID))
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"student_id", "name", "email", "status", "enrolled_at"})
+	for _, r := range roster {
+		_ = w.Write([]string{
+			strconv.FormatUint(uint64(r.StudentID), 10),
+			r.Name,
+			r.Email,
+			r.Status,
+			r.EnrolledAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var signageService *services.SignageService
+
+// signageToken autentica la pantalla con la cabecera X-Signage-Token. No se
+// acepta en la URL, donde quedaría en logs e historiales.
+func signageToken(c *gin.Context) (*models.SignageToken, bool) {
+	token := c.GetHeader("X-Signage-Token")
+	t, err := signageService.Authenticate(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return t, true
+}
+
+func respondSignageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSignageScope):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInstantBookingOff), errors.Is(err, services.ErrRoomNotFree),
+		errors.Is(err, services.ErrInstantBookingRejected):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "room not found" || err.Error() == "building not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetRoomSignage devuelve el turno actual y el siguiente del aula y cuánto tiempo sigue libre
+func GetRoomSignage(c *gin.Context) {
+	t, ok := signageToken(c)
+	if !ok {
+		return
+	}
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	board, err := signageService.RoomBoard(t, uint(id64))
+	if err != nil {
+		respondSignageError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, board)
+}
+
+// GetBuildingSignage devuelve el estado de todas las aulas del edificio
+func GetBuildingSignage(c *gin.Context) {
+	t, ok := signageToken(c)
+	if !ok {
+		return
+	}
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	board, err := signageService.BuildingBoard(t, uint(id64))
+	if err != nil {
+		respondSignageError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, board)
+}
+
+type instantBookingReq struct {
+	Purpose string `json:"purpose"`
+}
+
+// InstantBookRoom reserva el aula por 30 minutos desde la pantalla de la puerta
+func InstantBookRoom(c *gin.Context) {
+	t, ok := signageToken(c)
+	if !ok {
+		return
+	}
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req instantBookingReq
+	// El cuerpo es opcional
+	_ = c.ShouldBindJSON(&req)
+	resv, err := signageService.InstantBook(t, uint(id64), req.Purpose)
+	if err != nil {
+		respondSignageError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         resv.ID,
+		"room_id":    resv.RoomID,
+		"title":      resv.Purpose,
+		"start_time": resv.StartTime,
+		"end_time":   resv.EndTime,
+	})
+}
+
+type signageTokenReq struct {
+	Name       string `json:"name" binding:"required"`
+	RoomID     *uint  `json:"room_id"`
+	BuildingID *uint  `json:"building_id"`
+}
+
+// CreateSignageToken crea el token de una pantalla; el valor sólo se devuelve aquí
+func CreateSignageToken(c *gin.Context) {
+	var req signageTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+	t, err := signageService.CreateToken(req.Name, req.RoomID, req.BuildingID, uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+func ListSignageTokens(c *gin.Context) {
+	list, err := signageService.ListTokens()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func RevokeSignageToken(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := signageService.RevokeToken(uint(id64)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var studentTimetableService = services.NewStudentTimetableService()
+
+// parseRangeParam acepta RFC3339 o una fecha YYYY-MM-DD (inicio del día local)
+func parseRangeParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", v, time.Local)
+}
+
+// GetMyTimetable devuelve el horario del estudiante autenticado entre from y to
+// (por defecto la semana que empieza hoy). Con ?format=ics se exporta como calendario.
+func GetMyTimetable(c *gin.Context) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 7)
+	if v := c.Query("from"); v != "" {
+		t, err := parseRangeParam(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := parseRangeParam(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+		// Una fecha sola incluye todo ese día
+		if len(v) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	entries, err := studentTimetableService.Get(uid, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("format") != "ics" {
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	events := make([]utils.ICSEvent, 0, len(entries))
+	for _, e := range entries {
+		ev := utils.ICSEvent{
+			UID:     fmt.Sprintf("reservation-%d@programcion-backend", e.ID),
+			Start:   e.StartTime,
+			End:     e.EndTime,
+			Summary: e.Purpose,
+		}
+		if e.Class != nil {
+			ev.Summary = e.Class.Name
+			ev.Description = e.Purpose
+		}
+		if e.Room != nil {
+			ev.Location = e.Room.Name
+			if e.Room.Building != nil {
+				ev.Location += ", " + e.Room.Building.Name
+			}
+		}
+		events = append(events, ev)
+	}
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=\"timetable.ics\"")
+	if err := utils.WriteICS(c.Writer, "Mi horario", events); err != nil {
+		c.Status(http.StatusInternalServerError)
+	}
+}
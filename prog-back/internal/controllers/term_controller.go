@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var termService = services.NewTermService()
+
+type termReq struct {
+	Name            string `json:"name" binding:"required"`
+	StartDate       string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate         string `json:"end_date" binding:"required"`
+	EnrollmentStart string `json:"enrollment_start"` // RFC3339
+	EnrollmentEnd   string `json:"enrollment_end"`
+}
+
+// toModel convierte el request en un Term, devolviendo el nombre del campo inválido
+func (req termReq) toModel(t *models.Term) (string, bool) {
+	st, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return "start_date", false
+	}
+	et, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return "end_date", false
+	}
+	t.Name = req.Name
+	t.StartDate = st
+	t.EndDate = et
+	t.EnrollmentStart = nil
+	t.EnrollmentEnd = nil
+	if req.EnrollmentStart != "" {
+		es, err := time.Parse(time.RFC3339, req.EnrollmentStart)
+		if err != nil {
+			return "enrollment_start", false
+		}
+		t.EnrollmentStart = &es
+	}
+	if req.EnrollmentEnd != "" {
+		ee, err := time.Parse(time.RFC3339, req.EnrollmentEnd)
+		if err != nil {
+			return "enrollment_end", false
+		}
+		t.EnrollmentEnd = &ee
+	}
+	return "", true
+}
+
+func CreateTerm(c *gin.Context) {
+	var req termReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var t models.Term
+	if field, ok := req.toModel(&t); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + field + " format"})
+		return
+	}
+	if err := termService.Create(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+func ListTerms(c *gin.Context) {
+	list, err := termService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func GetTerm(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, _ := strconv.ParseUint(idStr, 10, 32)
+	t, err := termService.Get(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func UpdateTerm(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, _ := strconv.ParseUint(idStr, 10, 32)
+	t, err := termService.Get(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	var req termReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if field, ok := req.toModel(t); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + field + " format"})
+		return
+	}
+	if err := termService.Update(t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+type holidayReq struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+func AddTermHoliday(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req holidayReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	d, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+		return
+	}
+	h, err := termService.AddHoliday(uint(id64), d, req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, h)
+}
+
+func DeleteTermHoliday(c *gin.Context) {
+	id64, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	hid64, _ := strconv.ParseUint(c.Param("holiday_id"), 10, 32)
+	if err := termService.DeleteHoliday(uint(id64), uint(hid64)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"programcion-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var timetableService *services.TimetableService
+
+// CreateTimetableProposal ejecuta el planificador automático de horarios
+func CreateTimetableProposal(c *gin.Context) {
+	var req services.TimetableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uidI, _ := c.Get("user_id")
+	uid, _ := uidI.(uint)
+
+	p, err := timetableService.Propose(req, uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+func ListTimetableProposals(c *gin.Context) {
+	termID, _ := strconv.ParseUint(c.Query("term_id"), 10, 32)
+	list, err := timetableService.List(uint(termID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func GetTimetableProposal(c *gin.Context) {
+	id64, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	p, err := timetableService.Get(uint(id64))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+func CommitTimetableProposal(c *gin.Context) {
+	id64, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	conflicts, err := timetableService.Commit(uint(id64))
+	if respondPersonConflict(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(conflicts) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "time slot not available (overlap)", "conflicts": conflicts})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "committed"})
+}
+
+func DiscardTimetableProposal(c *gin.Context) {
+	id64, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := timetableService.Discard(uint(id64)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "discarded"})
+}
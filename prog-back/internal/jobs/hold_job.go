@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const holdSweepInterval = 30 * time.Second
+
+// StartHoldSweeper expira periódicamente los bloqueos temporales vencidos
+func StartHoldSweeper(cfg *config.Config) {
+	svc := services.NewReservationService(cfg)
+	go func() {
+		ticker := time.NewTicker(holdSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			n, err := svc.ExpireHolds()
+			if err != nil {
+				log.WithError(err).Error("Error expirando bloqueos temporales")
+				continue
+			}
+			if n > 0 {
+				log.Infof("Bloqueos temporales expirados: %d", n)
+			}
+		}
+	}()
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const noShowInterval = time.Minute
+
+// StartNoShowReleaser lanza en segundo plano la liberación automática de
+// reservas sin check-in, cfg.Reservations.NoShowGraceMinutes minutos después
+// del inicio de la reserva.
+func StartNoShowReleaser(cfg *config.Config) {
+	grace := cfg.Reservations.NoShowGraceMinutes
+	svc := services.NewReservationService(cfg)
+	go func() {
+		ticker := time.NewTicker(noShowInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			n, err := svc.ReleaseNoShows(time.Duration(grace) * time.Minute)
+			if err != nil {
+				log.WithError(err).Error("Error liberando reservas sin check-in")
+				continue
+			}
+			if n > 0 {
+				log.Infof("Reservas marcadas como NO_SHOW: %d", n)
+			}
+		}
+	}()
+	log.Infof("Liberación automática de no-shows activa (margen %d min)", grace)
+}
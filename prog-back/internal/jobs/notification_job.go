@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const notificationDispatchInterval = 5 * time.Second
+
+// StartNotificationDispatcher entrega periódicamente las notificaciones encoladas
+func StartNotificationDispatcher(cfg *config.Config) {
+	svc := services.NewNotificationService(cfg)
+	go func() {
+		ticker := time.NewTicker(notificationDispatchInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			n, err := svc.DispatchDue()
+			if err != nil {
+				log.WithError(err).Error("Error despachando notificaciones")
+				continue
+			}
+			if n > 0 {
+				log.Debugf("Notificaciones entregadas: %d", n)
+			}
+		}
+	}()
+}
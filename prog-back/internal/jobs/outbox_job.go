@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	outboxRelayInterval = time.Second
+	outboxPruneInterval = time.Hour
+	outboxRetention     = 7 * 24 * time.Hour
+)
+
+// StartOutboxRelay publica periódicamente los eventos del outbox en los
+// sinks: suscriptores del proceso, webhooks y el broker elegido en
+// cfg.Events.Broker (memory; none lo desactiva)
+func StartOutboxRelay(cfg *config.Config) {
+	sinks := []services.EventSink{services.DomainEvents, services.NewWebhookSink(cfg)}
+	if cfg.Events.Broker == "memory" {
+		sinks = append(sinks, services.NewBrokerSink(services.LocalBroker))
+	}
+	relay := services.NewOutboxRelay(sinks...)
+	go func() {
+		ticker := time.NewTicker(outboxRelayInterval)
+		defer ticker.Stop()
+		lastPrune := time.Time{}
+		for range ticker.C {
+			n, err := relay.RelayPending()
+			if err != nil {
+				log.WithError(err).Error("Error publicando eventos del outbox")
+				continue
+			}
+			if n > 0 {
+				log.Debugf("Eventos del outbox publicados: %d", n)
+			}
+			if time.Since(lastPrune) >= outboxPruneInterval {
+				lastPrune = time.Now()
+				if _, err := relay.Prune(outboxRetention); err != nil {
+					log.WithError(err).Error("Error depurando el outbox")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const reminderInterval = time.Minute
+
+// StartReminderScheduler envía los recordatorios previos a cada reserva y el
+// resumen diario de agenda. cfg.Reminders define con cuánta anticipación y la
+// hora local del resumen (-1 lo desactiva). Cada envío se reclama en la base
+// de datos, por lo que varias réplicas o un reinicio no producen duplicados.
+func StartReminderScheduler(cfg *config.Config) {
+	offsets := cfg.Reminders.Offsets
+	digestHour := cfg.Reminders.DigestHour
+
+	svc := services.NewReminderService(cfg)
+	run := func() {
+		now := time.Now()
+		if n, err := svc.SendReminders(offsets, now); err != nil {
+			log.WithError(err).Error("Error enviando recordatorios de reservas")
+		} else if n > 0 {
+			log.Infof("Recordatorios de reservas enviados: %d", n)
+		}
+		if digestHour >= 0 {
+			if n, err := svc.SendDigests(digestHour, now); err != nil {
+				log.WithError(err).Error("Error enviando resúmenes de agenda")
+			} else if n > 0 {
+				log.Infof("Resúmenes de agenda enviados: %d", n)
+			}
+		}
+		if err := svc.PruneClaims(now); err != nil {
+			log.WithError(err).Warn("Error limpiando recordatorios viejos")
+		}
+	}
+	go func() {
+		run()
+		ticker := time.NewTicker(reminderInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+	log.Infof("Recordatorios activos (anticipación %v, resumen a las %d h)", offsets, digestHour)
+}
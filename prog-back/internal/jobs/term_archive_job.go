@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const termArchiveInterval = time.Hour
+
+// StartTermArchiver archiva las clases de los períodos académicos finalizados
+func StartTermArchiver() {
+	svc := services.NewTermService()
+	run := func() {
+		n, err := svc.ArchiveEnded()
+		if err != nil {
+			log.WithError(err).Error("Error archivando períodos finalizados")
+			return
+		}
+		if n > 0 {
+			log.Infof("Períodos académicos archivados: %d", n)
+		}
+	}
+	go func() {
+		run()
+		ticker := time.NewTicker(termArchiveInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"time"
+
+	"programcion-backend/internal/services"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const webhookDispatchInterval = 5 * time.Second
+
+// StartWebhookDispatcher entrega periódicamente los webhooks pendientes
+func StartWebhookDispatcher(cfg *config.Config) {
+	svc := services.NewWebhookService(cfg)
+	go func() {
+		ticker := time.NewTicker(webhookDispatchInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			n, err := svc.DispatchDue()
+			if err != nil {
+				log.WithError(err).Error("Error despachando webhooks")
+				continue
+			}
+			if n > 0 {
+				log.Debugf("Webhooks entregados: %d", n)
+			}
+		}
+	}()
+}
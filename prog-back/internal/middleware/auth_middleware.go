@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 
@@ -10,7 +9,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func RequireAuthentication() gin.HandlerFunc {
+// RequireAuthentication valida el JWT (cabecera Bearer o cookie access_token)
+// firmado con secret
+func RequireAuthentication(secret string) gin.HandlerFunc {
+	key := []byte(secret)
 	return func(c *gin.Context) {
 		tokenStr := ""
 		auth := c.GetHeader("Authorization")
@@ -30,12 +32,8 @@ func RequireAuthentication() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing auth token"})
 			return
 		}
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			secret = "dev_secret"
-		}
 		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
+			return key, nil
 		})
 		if err != nil || !token.Valid {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Announcement es un aviso publicado en una clase para sus estudiantes.
+// AuthorID es nil en los avisos generados automáticamente por el sistema.
+type Announcement struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ClassID       uint      `gorm:"index;not null" json:"class_id"`
+	AuthorID      *uint     `json:"author_id,omitempty"`
+	Author        *User     `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	ReservationID *uint     `json:"reservation_id,omitempty"` // sesión a la que se refiere, si aplica
+	Title         string    `gorm:"not null" json:"title"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// AnnouncementRead marca un aviso como leído por un usuario
+type AnnouncementRead struct {
+	AnnouncementID uint      `gorm:"primaryKey" json:"announcement_id"`
+	UserID         uint      `gorm:"primaryKey" json:"user_id"`
+	ReadAt         time.Time `json:"read_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Attendance registra la asistencia de un estudiante a una sesión de clase
+// (reserva vinculada a la clase)
+type Attendance struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ReservationID uint      `gorm:"uniqueIndex:idx_attendance_resv_student;not null" json:"reservation_id"`
+	StudentID     uint      `gorm:"uniqueIndex:idx_attendance_resv_student;not null;index" json:"student_id"`
+	Student       *User     `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Status        string    `gorm:"not null" json:"status"` // PRESENT|LATE|ABSENT|EXCUSED
+	MarkedByID    *uint     `json:"marked_by_id"`           // el propio estudiante si marcó con el código; nil si la cuenta fue purgada
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -7,20 +7,39 @@ import (
 )
 
 type Class struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"not null" json:"name"`
-	Description string         `json:"description"`
-	Subject     string         `json:"subject"`
-	ProfessorID uint           `gorm:"not null" json:"professor_id"`
-	Professor   *User          `gorm:"foreignKey:ProfessorID" json:"professor,omitempty"`
-	Students    []User         `gorm:"many2many:class_students;" json:"students,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                   uint           `gorm:"primaryKey" json:"id"`
+	Name                 string         `gorm:"not null" json:"name"`
+	Description          string         `json:"description"`
+	Subject              string         `json:"subject"`
+	ProfessorID          uint           `gorm:"not null" json:"professor_id"`
+	Professor            *User          `gorm:"foreignKey:ProfessorID" json:"professor,omitempty"`
+	TermID               *uint          `gorm:"index" json:"term_id,omitempty"`
+	Term                 *Term          `gorm:"foreignKey:TermID" json:"term,omitempty"`
+	ArchivedAt           *time.Time     `json:"archived_at,omitempty"`         // clase de sólo lectura tras finalizar el período
+	MaxStudents          int            `gorm:"default:0" json:"max_students"` // 0 = sin límite
+	JoinCode             *string        `gorm:"uniqueIndex" json:"-"`          // auto-inscripción; sólo visible vía /join-code
+	JoinEnabled          bool           `gorm:"default:false" json:"-"`
+	JoinRequiresApproval bool           `gorm:"default:false" json:"-"`
+	Students             []User         `gorm:"many2many:class_students;" json:"students,omitempty"`
+	Staff                []ClassStaff   `gorm:"foreignKey:ClassID" json:"staff,omitempty"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type ClassStudent struct {
 	ClassID    uint      `gorm:"primaryKey" json:"class_id"`
 	StudentID  uint      `gorm:"primaryKey" json:"student_id"`
-	EnrolledAt time.Time `gorm:"autoCreateTime" json:"enrolled_at"`
+	Status     string    `gorm:"not null;default:'ENROLLED'" json:"status"` // ENROLLED|PENDING|WAITLISTED|ARCHIVED
+	EnrolledAt time.Time `gorm:"autoCreateTime" json:"enrolled_at"`         // en lista de espera: fecha de solicitud (define el orden)
+}
+
+// ClassStaff son los docentes adicionales de una clase. El profesor de la
+// clase (ProfessorID) es siempre el dueño y no necesita fila propia.
+type ClassStaff struct {
+	ClassID   uint      `gorm:"primaryKey" json:"class_id"`
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	User      *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role      string    `gorm:"not null" json:"role"` // OWNER|CO_TEACHER|TA
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ClassSession es un bloque semanal del horario de una clase (ej. lunes 10:00-11:30 en Lab 2).
+// Cada sesión genera una reserva por semana dentro del período académico de la clase.
+type ClassSession struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ClassID   uint      `gorm:"index;not null" json:"class_id"`
+	RoomID    uint      `gorm:"index;not null" json:"room_id"`
+	Room      *Room     `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	Weekday   int       `gorm:"not null" json:"weekday"`    // 0=domingo ... 6=sábado
+	StartTime string    `gorm:"not null" json:"start_time"` // HH:MM
+	EndTime   string    `gorm:"not null" json:"end_time"`   // HH:MM
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Warnings []ReservationConflict `gorm:"-" json:"warnings,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Exam es una instancia de examen de una clase que puede ocupar varias aulas
+type Exam struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ClassID     uint       `gorm:"index;not null" json:"class_id"`
+	Class       *Class     `gorm:"foreignKey:ClassID" json:"class,omitempty"`
+	Title       string     `gorm:"not null" json:"title"`
+	StartTime   time.Time  `gorm:"not null;index" json:"start_time"`
+	EndTime     time.Time  `gorm:"not null;index" json:"end_time"`
+	Status      string     `gorm:"not null;default:'ACTIVE'" json:"status"` // ACTIVE|CANCELLED
+	CreatedByID *uint      `json:"created_by_id"`                           // nil si la cuenta fue purgada
+	Rooms       []ExamRoom `gorm:"foreignKey:ExamID" json:"rooms,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ExamRoom es un aula asignada al examen, con su reserva, vigilantes y asientos
+type ExamRoom struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	ExamID        uint              `gorm:"index;not null" json:"exam_id"`
+	RoomID        uint              `gorm:"index;not null" json:"room_id"`
+	Room          *Room             `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	ReservationID uint              `gorm:"not null" json:"reservation_id"`
+	Invigilators  []ExamInvigilator `gorm:"foreignKey:ExamRoomID" json:"invigilators,omitempty"`
+	Seats         []ExamSeat        `gorm:"foreignKey:ExamRoomID" json:"seats,omitempty"`
+}
+
+type ExamInvigilator struct {
+	ExamRoomID uint  `gorm:"primaryKey" json:"exam_room_id"`
+	UserID     uint  `gorm:"primaryKey" json:"user_id"`
+	User       *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ExamID     uint  `gorm:"index;not null" json:"exam_id"`
+}
+
+type ExamSeat struct {
+	ExamRoomID uint  `gorm:"primaryKey" json:"exam_room_id"`
+	StudentID  uint  `gorm:"primaryKey" json:"student_id"`
+	Student    *User `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	SeatNumber int   `gorm:"not null" json:"seat_number"`
+}
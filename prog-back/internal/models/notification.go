@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Notification es un mensaje de la bandeja de entrada in-app de un usuario
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	Event     string     `gorm:"not null" json:"event"`
+	Title     string     `gorm:"not null" json:"title"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationDelivery es un envío pendiente por un canal. El despachador lo
+// procesa en segundo plano y lo reintenta con espera creciente si falla.
+type NotificationDelivery struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"index;not null" json:"user_id"`
+	Event         string     `gorm:"not null" json:"event"`
+	Channel       string     `gorm:"not null" json:"channel"` // EMAIL|IN_APP
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	Status        string     `gorm:"not null;default:'PENDING';index" json:"status"` // PENDING|SENT|FAILED
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NotificationPreference indica por qué canales quiere el usuario recibir un
+// evento. Sin fila se usan ambos canales.
+type NotificationPreference struct {
+	UserID uint   `gorm:"primaryKey" json:"-"`
+	Event  string `gorm:"primaryKey" json:"event"`
+	Email  bool   `gorm:"not null" json:"email"`
+	InApp  bool   `gorm:"not null" json:"in_app"`
+}
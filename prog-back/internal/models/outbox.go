@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Eventos de dominio registrados en el outbox
+const (
+	EventReservationCreated   = "reservation.created"
+	EventReservationCancelled = "reservation.cancelled"
+	EventReservationCheckedIn = "reservation.checked_in"
+	EventReservationNoShow    = "reservation.no_show"
+	EventReservationExpired   = "reservation.expired"
+	EventRoomUpdated          = "room.updated"
+	EventClassCreated         = "class.created"
+	EventClassUpdated         = "class.updated"
+	EventClassDeleted         = "class.deleted"
+	EventClassRestored        = "class.restored"
+	EventEnrollmentChanged    = "class.enrollment.changed"
+	EventUserCreated          = "user.created"
+	EventUserUpdated          = "user.updated"
+	EventUserDeleted          = "user.deleted"
+	EventUserRestored         = "user.restored"
+)
+
+// OutboxEvent es un evento de dominio guardado en la misma transacción que el
+// cambio que lo origina. El relay lo publica después en los sinks configurados;
+// el ID define el orden de publicación.
+type OutboxEvent struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	AggregateType string          `gorm:"not null;index:idx_outbox_aggregate" json:"aggregate_type"` // reservation|room|class|user
+	AggregateID   uint            `gorm:"not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	Event         string          `gorm:"not null" json:"event"`
+	Payload       json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `gorm:"index" json:"published_at,omitempty"`
+	Attempts      int             `gorm:"not null;default:0" json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `gorm:"not null" json:"next_attempt_at"`
+}
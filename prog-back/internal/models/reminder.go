@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// ReminderClaim registra que un recordatorio o resumen ya se envió. La clave
+// es única, así que sólo una réplica del backend puede reclamarlo.
+type ReminderClaim struct {
+	Key       string    `gorm:"primaryKey" json:"key"` // ej. reminder:42:1h0m0s, digest:7:2024-05-02
+	CreatedAt time.Time `json:"created_at"`
+}
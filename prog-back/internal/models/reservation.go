@@ -3,18 +3,36 @@ package models
 import "time"
 
 type Reservation struct {
-	ID                 uint      `gorm:"primaryKey" json:"id"`
-	RoomID             uint      `gorm:"index;not null" json:"room_id"`
-	Room               *Room     `gorm:"foreignKey:RoomID" json:"room,omitempty"`
-	UserID             uint      `gorm:"index;not null" json:"user_id"`
-	User               *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	ClassID            *uint     `gorm:"index" json:"class_id,omitempty"`
-	Class              *Class    `gorm:"foreignKey:ClassID" json:"class,omitempty"`
-	StartTime          time.Time `gorm:"not null;index" json:"start_time"`
-	EndTime            time.Time `gorm:"not null;index" json:"end_time"`
-	Purpose            string    `json:"purpose"`
-	EstimatedAttendees int       `json:"estimated_attendees"`
-	Status             string    `gorm:"not null;default:'ACTIVE'" json:"status"` // ACTIVE|CANCELLED
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	RoomID             uint       `gorm:"index;not null" json:"room_id"`
+	Room               *Room      `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	UserID             uint       `gorm:"index;not null" json:"user_id"`
+	User               *User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ClassID            *uint      `gorm:"index" json:"class_id,omitempty"`
+	Class              *Class     `gorm:"foreignKey:ClassID" json:"class,omitempty"`
+	TermID             *uint      `gorm:"index" json:"term_id,omitempty"`
+	SessionID          *uint      `gorm:"index" json:"session_id,omitempty"` // generada desde el horario de la clase
+	ExamID             *uint      `gorm:"index" json:"exam_id,omitempty"`
+	SignageTokenID     *uint      `gorm:"index" json:"signage_token_id,omitempty"` // reserva instantánea hecha desde esa pantalla
+	Kind               string     `gorm:"not null;default:'REGULAR'" json:"kind"`  // REGULAR|EXAM
+	StartTime          time.Time  `gorm:"not null;index" json:"start_time"`
+	EndTime            time.Time  `gorm:"not null;index" json:"end_time"`
+	Purpose            string     `json:"purpose"`
+	EstimatedAttendees int        `json:"estimated_attendees"`
+	Status             string     `gorm:"not null;default:'ACTIVE'" json:"status"` // ACTIVE|CANCELLED|NO_SHOW|HELD|EXPIRED
+	Private            bool       `gorm:"not null;default:false" json:"private"`   // oculta motivo y clase en vistas públicas
+	CheckedInAt        *time.Time `json:"checked_in_at,omitempty"`
+	HoldExpiresAt      *time.Time `gorm:"index" json:"hold_expires_at,omitempty"` // solo para bloqueos temporales (HELD)
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	Warnings []ReservationConflict `gorm:"-" json:"warnings,omitempty"` // choques de personas tolerados (modo warn)
+}
+
+// ReservationConflict es una reserva existente que ocupa a una persona
+// involucrada en la nueva reserva
+type ReservationConflict struct {
+	Reason      string      `json:"reason"` // USER|PROFESSOR|STUDENT
+	UserID      uint        `json:"user_id,omitempty"`
+	Reservation Reservation `json:"reservation"`
 }
@@ -3,13 +3,16 @@ package models
 import "time"
 
 type Room struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	BuildingID  uint      `gorm:"index;not null" json:"building_id"`
-	Building    *Building `gorm:"foreignKey:BuildingID" json:"building,omitempty"`
-	Name        string    `gorm:"not null" json:"name"`
-	Capacity    int       `gorm:"not null" json:"capacity"`
-	Resources   string    `json:"resources"` // JSON string or comma-separated
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	BuildingID     uint      `gorm:"index;not null" json:"building_id"`
+	Building       *Building `gorm:"foreignKey:BuildingID" json:"building,omitempty"`
+	Name           string    `gorm:"not null" json:"name"`
+	Capacity       int       `gorm:"not null" json:"capacity"`
+	ExamCapacity   int       `json:"exam_capacity"` // plazas en modo examen (0 = la mitad de capacity)
+	Resources      string    `json:"resources"`     // JSON string or comma-separated
+	Description    string    `json:"description"`
+	CheckinToken   string    `gorm:"index" json:"-"`                                // token del QR de check-in pegado en la puerta
+	InstantBooking bool      `gorm:"not null;default:false" json:"instant_booking"` // reserva de 30 min desde la pantalla de la puerta
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
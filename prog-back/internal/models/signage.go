@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SignageToken autoriza a una pantalla (tablet en la puerta o cartelera del
+// edificio) a consultar la ocupación de un aula o de todo un edificio. Sólo se
+// guarda el hash del token; el valor se muestra una única vez al crearlo.
+type SignageToken struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"`
+	RoomID      *uint      `gorm:"index" json:"room_id,omitempty"`     // alcance: un aula...
+	BuildingID  *uint      `gorm:"index" json:"building_id,omitempty"` // ...o un edificio completo
+	CreatedByID *uint      `json:"created_by_id"`                      // titular de las reservas instantáneas; nil si se purgó (sólo tokens revocados)
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
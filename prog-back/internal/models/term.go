@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Term representa un período académico (cuatrimestre/semestre)
+type Term struct {
+	ID              uint          `gorm:"primaryKey" json:"id"`
+	Name            string        `gorm:"not null" json:"name"`
+	StartDate       time.Time     `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time     `gorm:"type:date;not null" json:"end_date"`
+	EnrollmentStart *time.Time    `json:"enrollment_start,omitempty"`
+	EnrollmentEnd   *time.Time    `json:"enrollment_end,omitempty"`
+	Holidays        []TermHoliday `gorm:"foreignKey:TermID" json:"holidays,omitempty"`
+	ArchivedAt      *time.Time    `json:"archived_at,omitempty"` // se completa al finalizar el período
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+type TermHoliday struct {
+	ID     uint      `gorm:"primaryKey" json:"id"`
+	TermID uint      `gorm:"index;not null" json:"term_id"`
+	Date   time.Time `gorm:"type:date;not null" json:"date"`
+	Name   string    `json:"name"`
+}
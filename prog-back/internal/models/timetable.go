@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TimetableProposal es el resultado del planificador automático de horarios.
+// Queda en DRAFT hasta que un admin la revisa y la confirma.
+type TimetableProposal struct {
+	ID          uint                  `gorm:"primaryKey" json:"id"`
+	TermID      uint                  `gorm:"index;not null" json:"term_id"`
+	CreatedByID *uint                 `json:"created_by_id"`                          // nil si la cuenta fue purgada
+	Status      string                `gorm:"not null;default:'DRAFT'" json:"status"` // DRAFT|COMMITTED|DISCARDED
+	Score       float64               `json:"score"`                                  // penalización por preferencias incumplidas (menor es mejor)
+	Assignments []TimetableAssignment `gorm:"foreignKey:ProposalID" json:"assignments"`
+	Unassigned  json.RawMessage       `gorm:"type:jsonb" json:"unassigned"` // clases/sesiones que no se pudieron ubicar
+	CommittedAt *time.Time            `json:"committed_at,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+type TimetableAssignment struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	ProposalID uint   `gorm:"index;not null" json:"proposal_id"`
+	ClassID    uint   `gorm:"not null" json:"class_id"`
+	RoomID     uint   `gorm:"not null" json:"room_id"`
+	Weekday    int    `gorm:"not null" json:"weekday"`
+	StartTime  string `gorm:"not null" json:"start_time"` // HH:MM
+	EndTime    string `gorm:"not null" json:"end_time"`
+}
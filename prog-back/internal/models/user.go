@@ -13,7 +13,22 @@ type User struct {
 	PasswordHash string         `gorm:"not null" json:"-"`
 	Role         string         `gorm:"not null" json:"role"` // ADMIN|PROFESSOR|STUDENT
 	IsConfirmed  bool           `gorm:"default:false" json:"is_confirmed"`
+	Locale       string         `gorm:"not null;default:'es'" json:"locale"` // es|en, idioma de las notificaciones
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+// UserInvite permite a una cuenta invitada (p. ej. desde un padrón) elegir su
+// contraseña. Sólo se guarda el hash del token, que viaja por correo.
+// ClassID es la clase del padrón: el invitado se inscribe en ella cuando su
+// cuenta queda confirmada.
+type UserInvite struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	ClassID    *uint      `gorm:"index" json:"class_id"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
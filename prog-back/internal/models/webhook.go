@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription es un endpoint externo que recibe eventos de dominio
+type WebhookSubscription struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	URL                 string     `gorm:"not null" json:"url"`
+	Secret              string     `gorm:"not null" json:"-"`      // clave HMAC; sólo se muestra al crear o rotar
+	Events              string     `gorm:"not null" json:"events"` // separados por coma; "*" = todos
+	Active              bool       `gorm:"not null;default:true" json:"active"`
+	ConsecutiveFailures int        `gorm:"not null;default:0" json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"` // deshabilitado automáticamente por fallos
+	CreatedByID         *uint      `json:"created_by_id"`         // nil si la cuenta fue purgada
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery es un intento de entrega de un evento a una suscripción
+type WebhookDelivery struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint            `gorm:"index;not null" json:"subscription_id"`
+	Event          string          `gorm:"not null" json:"event"`
+	EventID        string          `gorm:"index;not null" json:"event_id"`
+	Payload        json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string          `gorm:"not null;default:'PENDING';index" json:"status"` // PENDING|SUCCEEDED|FAILED
+	Attempts       int             `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time       `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnnouncementRepository struct{}
+
+func NewAnnouncementRepository() *AnnouncementRepository { return &AnnouncementRepository{} }
+
+func (r *AnnouncementRepository) Create(a *models.Announcement) error {
+	return db.GetDB().Create(a).Error
+}
+
+func (r *AnnouncementRepository) GetByID(id uint) (*models.Announcement, error) {
+	var a models.Announcement
+	if err := db.GetDB().Preload("Author").First(&a, id).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) FindByClassIDs(classIDs []uint) ([]models.Announcement, error) {
+	var list []models.Announcement
+	if len(classIDs) == 0 {
+		return list, nil
+	}
+	err := db.GetDB().Where("class_id IN ?", classIDs).Preload("Author").
+		Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *AnnouncementRepository) Delete(id uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("announcement_id = ?", id).Delete(&models.AnnouncementRead{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Announcement{}, id).Error
+	})
+}
+
+func (r *AnnouncementRepository) MarkRead(announcementID, userID uint, at time.Time) error {
+	return db.GetDB().Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.AnnouncementRead{AnnouncementID: announcementID, UserID: userID, ReadAt: at}).Error
+}
+
+// ReadAtByUser devuelve cuándo leyó el usuario cada uno de los avisos indicados
+func (r *AnnouncementRepository) ReadAtByUser(userID uint, announcementIDs []uint) (map[uint]time.Time, error) {
+	out := map[uint]time.Time{}
+	if len(announcementIDs) == 0 {
+		return out, nil
+	}
+	var reads []models.AnnouncementRead
+	if err := db.GetDB().Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).Find(&reads).Error; err != nil {
+		return nil, err
+	}
+	for _, rd := range reads {
+		out[rd.AnnouncementID] = rd.ReadAt
+	}
+	return out, nil
+}
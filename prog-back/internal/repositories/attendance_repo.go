@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm/clause"
+)
+
+type AttendanceRepository struct{}
+
+func NewAttendanceRepository() *AttendanceRepository { return &AttendanceRepository{} }
+
+// Upsert crea o reemplaza el registro del estudiante para la reserva
+func (r *AttendanceRepository) Upsert(a *models.Attendance) error {
+	return db.GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "reservation_id"}, {Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "marked_by_id", "note", "updated_at"}),
+	}).Create(a).Error
+}
+
+func (r *AttendanceRepository) Get(reservationID, studentID uint) (*models.Attendance, error) {
+	var a models.Attendance
+	err := db.GetDB().Where("reservation_id = ? AND student_id = ?", reservationID, studentID).First(&a).Error
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttendanceRepository) ListByReservation(reservationID uint) ([]models.Attendance, error) {
+	var list []models.Attendance
+	err := db.GetDB().Where("reservation_id = ?", reservationID).Find(&list).Error
+	return list, err
+}
+
+// HeldSession es una sesión de la clase que ya comenzó
+type HeldSession struct {
+	ID        uint
+	StartTime time.Time
+}
+
+// HeldSessions devuelve las reservas activas de la clase que ya comenzaron.
+// Los exámenes no cuentan como sesiones de clase
+func (r *AttendanceRepository) HeldSessions(classID uint, now time.Time) ([]HeldSession, error) {
+	var out []HeldSession
+	err := db.GetDB().Model(&models.Reservation{}).
+		Select("id, start_time").
+		Where("class_id = ? AND status = ? AND start_time <= ?", classID, "ACTIVE", now).
+		Where("kind <> ?", "EXAM").
+		Order("start_time ASC").
+		Scan(&out).Error
+	return out, err
+}
+
+// AttendanceMark es el estado registrado de un estudiante en una sesión
+type AttendanceMark struct {
+	ReservationID uint
+	StudentID     uint
+	Status        string
+}
+
+func (r *AttendanceRepository) ListMarks(reservationIDs []uint) ([]AttendanceMark, error) {
+	var out []AttendanceMark
+	if len(reservationIDs) == 0 {
+		return out, nil
+	}
+	err := db.GetDB().Model(&models.Attendance{}).
+		Select("reservation_id, student_id, status").
+		Where("reservation_id IN ?", reservationIDs).
+		Scan(&out).Error
+	return out, err
+}
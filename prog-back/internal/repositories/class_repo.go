@@ -1,8 +1,14 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"programcion-backend/internal/models"
 	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ClassRepository struct{}
@@ -12,64 +18,509 @@ func NewClassRepository() *ClassRepository {
 }
 
 func (r *ClassRepository) Create(class *models.Class) error {
-	return db.GetDB().Create(class).Error
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(class).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "class", class.ID, models.EventClassCreated, class)
+	})
 }
 
 func (r *ClassRepository) FindByID(id uint) (*models.Class, error) {
 	var class models.Class
-	err := db.GetDB().Preload("Professor").Preload("Students").First(&class, id).Error
-	return &class, err
+	err := db.GetDB().Preload("Professor").Preload("Term").Preload("Staff.User").First(&class, id).Error
+	if err != nil {
+		return &class, err
+	}
+	list := []models.Class{class}
+	err = attachEnrolled(list)
+	return &list[0], err
 }
 
-func (r *ClassRepository) FindByProfessorID(professorID uint) ([]models.Class, error) {
+// attachEnrolled completa Students sólo con los inscriptos confirmados
+// (las solicitudes PENDING no cuentan como alumnos de la clase)
+func attachEnrolled(classes []models.Class) error {
+	if len(classes) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(classes))
+	for _, c := range classes {
+		ids = append(ids, c.ID)
+	}
+	var links []models.ClassStudent
+	if err := db.GetDB().Where("class_id IN ? AND status = ?", ids, "ENROLLED").Find(&links).Error; err != nil {
+		return err
+	}
+	studentIDs := make([]uint, 0, len(links))
+	for _, l := range links {
+		studentIDs = append(studentIDs, l.StudentID)
+	}
+	var users []models.User
+	if len(studentIDs) > 0 {
+		if err := db.GetDB().Where("id IN ?", studentIDs).Find(&users).Error; err != nil {
+			return err
+		}
+	}
+	byID := make(map[uint]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	byClass := make(map[uint][]models.User)
+	for _, l := range links {
+		if u, ok := byID[l.StudentID]; ok {
+			byClass[l.ClassID] = append(byClass[l.ClassID], u)
+		}
+	}
+	for i := range classes {
+		classes[i].Students = byClass[classes[i].ID]
+	}
+	return nil
+}
+
+// FindByProfessorID devuelve las clases que el profesor dicta como dueño o
+// como parte del equipo docente. termID = 0 significa sin filtro de período
+func (r *ClassRepository) FindByProfessorID(professorID, termID uint) ([]models.Class, error) {
 	var classes []models.Class
-	err := db.GetDB().Where("professor_id = ?", professorID).Preload("Professor").Preload("Students").Find(&classes).Error
-	return classes, err
+	q := db.GetDB().Where("professor_id = ? OR id IN (SELECT class_id FROM class_staffs WHERE user_id = ?)", professorID, professorID)
+	if termID != 0 {
+		q = q.Where("term_id = ?", termID)
+	}
+	if err := q.Preload("Professor").Find(&classes).Error; err != nil {
+		return classes, err
+	}
+	return classes, attachEnrolled(classes)
 }
 
-func (r *ClassRepository) FindAll() ([]models.Class, error) {
+func (r *ClassRepository) FindAll(termID uint) ([]models.Class, error) {
 	var classes []models.Class
-	err := db.GetDB().Preload("Professor").Find(&classes).Error
+	q := db.GetDB().Preload("Professor")
+	if termID != 0 {
+		q = q.Where("term_id = ?", termID)
+	}
+	err := q.Find(&classes).Error
 	return classes, err
 }
 
 func (r *ClassRepository) Update(class *models.Class) error {
-	return db.GetDB().Save(class).Error
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(class).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "class", class.ID, models.EventClassUpdated, class)
+	})
 }
 
+// Delete borra la clase de forma lógica: cancela sus reservas futuras y
+// archiva las inscripciones. Las solicitudes y la lista de espera se descartan.
 func (r *ClassRepository) Delete(id uint) error {
-	return db.GetDB().Delete(&models.Class{}, id).Error
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if _, err := setReservationStatus(tx, "CANCELLED", models.EventReservationCancelled,
+			"class_id = ? AND status IN ? AND start_time >= ?", id, []string{"ACTIVE", "HELD"}, time.Now()); err != nil {
+			return err
+		}
+		var links []models.ClassStudent
+		if err := tx.Where("class_id = ?", id).Order("student_id ASC").Find(&links).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ClassStudent{}).
+			Where("class_id = ? AND status = ?", id, "ENROLLED").
+			Update("status", "ARCHIVED").Error; err != nil {
+			return err
+		}
+		if err := tx.Where("class_id = ? AND status IN ?", id, []string{"PENDING", "WAITLISTED"}).
+			Delete(&models.ClassStudent{}).Error; err != nil {
+			return err
+		}
+		for _, l := range links {
+			status := ""
+			switch l.Status {
+			case "ENROLLED":
+				status = "ARCHIVED"
+			case "ARCHIVED":
+				continue
+			}
+			if err := appendEnrollmentChange(tx, id, l.StudentID, l.Status, status); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&models.Class{}, id).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "class", id, models.EventClassDeleted, map[string]uint{"id": id})
+	})
+}
+
+// FindDeleted devuelve las clases borradas lógicamente
+func (r *ClassRepository) FindDeleted() ([]models.Class, error) {
+	var classes []models.Class
+	err := db.GetDB().Unscoped().Where("deleted_at IS NOT NULL").
+		Preload("Professor").Order("deleted_at DESC").Find(&classes).Error
+	return classes, err
+}
+
+func (r *ClassRepository) FindDeletedByID(id uint) (*models.Class, error) {
+	var class models.Class
+	if err := db.GetDB().Unscoped().Where("deleted_at IS NOT NULL").First(&class, id).Error; err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// Restore recupera una clase borrada y reactiva las inscripciones archivadas
+// de los estudiantes que siguen existiendo
+func (r *ClassRepository) Restore(id uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Class{}).Where("id = ?", id).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		var restored []uint
+		if err := tx.Model(&models.ClassStudent{}).
+			Where("class_id = ? AND status = ?", id, "ARCHIVED").
+			Where("student_id IN (SELECT id FROM users WHERE deleted_at IS NULL)").
+			Order("student_id ASC").Pluck("student_id", &restored).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ClassStudent{}).
+			Where("class_id = ? AND status = ? AND student_id IN ?", id, "ARCHIVED", restored).
+			Update("status", "ENROLLED").Error; err != nil {
+			return err
+		}
+		var class models.Class
+		if err := tx.First(&class, id).Error; err != nil {
+			return err
+		}
+		if err := appendOutbox(tx, "class", id, models.EventClassRestored, &class); err != nil {
+			return err
+		}
+		for _, studentID := range restored {
+			if err := appendEnrollmentChange(tx, id, studentID, "ARCHIVED", "ENROLLED"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Purge elimina definitivamente una clase borrada junto con sus inscripciones,
+// equipo, sesiones, avisos y exámenes. Las reservas se conservan como historial.
+func (r *ClassRepository) Purge(id uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		steps := []string{
+			"UPDATE reservations SET session_id = NULL WHERE session_id IN (SELECT id FROM class_sessions WHERE class_id = ?)",
+			"UPDATE reservations SET exam_id = NULL WHERE exam_id IN (SELECT id FROM exams WHERE class_id = ?)",
+			"UPDATE reservations SET class_id = NULL WHERE class_id = ?",
+			"DELETE FROM exam_seats WHERE exam_room_id IN (SELECT er.id FROM exam_rooms er JOIN exams e ON e.id = er.exam_id WHERE e.class_id = ?)",
+			"DELETE FROM exam_invigilators WHERE exam_id IN (SELECT id FROM exams WHERE class_id = ?)",
+			"DELETE FROM exam_rooms WHERE exam_id IN (SELECT id FROM exams WHERE class_id = ?)",
+			"DELETE FROM exams WHERE class_id = ?",
+			"DELETE FROM announcement_reads WHERE announcement_id IN (SELECT id FROM announcements WHERE class_id = ?)",
+			"DELETE FROM announcements WHERE class_id = ?",
+			"DELETE FROM class_sessions WHERE class_id = ?",
+			"DELETE FROM class_staffs WHERE class_id = ?",
+			"DELETE FROM class_students WHERE class_id = ?",
+		}
+		for _, q := range steps {
+			if err := tx.Exec(q, id).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&models.Class{}, id).Error
+	})
+}
+
+// CountByProfessorUnscoped cuenta las clases del profesor, incluidas las borradas
+func (r *ClassRepository) CountByProfessorUnscoped(professorID uint) (int64, error) {
+	var count int64
+	err := db.GetDB().Unscoped().Model(&models.Class{}).Where("professor_id = ?", professorID).Count(&count).Error
+	return count, err
+}
+
+// CountByProfessor cuenta las clases vigentes (no borradas) del profesor
+func (r *ClassRepository) CountByProfessor(professorID uint) (int64, error) {
+	var count int64
+	err := db.GetDB().Model(&models.Class{}).Where("professor_id = ?", professorID).Count(&count).Error
+	return count, err
+}
+
+// AddStudent inscribe al estudiante respetando el cupo (capacity, 0 = sin
+// límite). Si el cupo está completo queda en lista de espera. Si tenía una
+// solicitud pendiente queda aprobada. Devuelve el estado final.
+func (r *ClassRepository) AddStudent(classID, studentID uint, capacity int) (string, error) {
+	status := "ENROLLED"
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Bloquear la clase para serializar inscripciones concurrentes
+		var class models.Class
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error; err != nil {
+			return err
+		}
+		var existing models.ClassStudent
+		err := tx.Where("class_id = ? AND student_id = ?", classID, studentID).First(&existing).Error
+		previous := ""
+		if err == nil {
+			previous = existing.Status
+		}
+		if err == nil && existing.Status == "ENROLLED" {
+			return nil
+		}
+		if err == nil && existing.Status == "WAITLISTED" {
+			status = "WAITLISTED"
+			return nil
+		}
+		if status, err = seatStatus(tx, classID, capacity); err != nil {
+			return err
+		}
+		if err := tx.Exec(
+			"INSERT INTO class_students (class_id, student_id, status, enrolled_at) VALUES (?, ?, ?, now()) "+
+				"ON CONFLICT (class_id, student_id) DO UPDATE SET status = EXCLUDED.status, enrolled_at = now()",
+			classID, studentID, status,
+		).Error; err != nil {
+			return err
+		}
+		return appendEnrollmentChange(tx, classID, studentID, previous, status)
+	})
+	return status, err
+}
+
+// seatStatus devuelve ENROLLED si la clase tiene cupo (capacity, 0 = sin
+// límite) o WAITLISTED si no. Cuenta bajo el lock de la fila de la clase.
+func seatStatus(tx *gorm.DB, classID uint, capacity int) (string, error) {
+	var class models.Class
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error; err != nil {
+		return "", err
+	}
+	if capacity <= 0 {
+		return "ENROLLED", nil
+	}
+	var count int64
+	if err := tx.Model(&models.ClassStudent{}).
+		Where("class_id = ? AND status = ?", classID, "ENROLLED").
+		Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count >= int64(capacity) {
+		return "WAITLISTED", nil
+	}
+	return "ENROLLED", nil
+}
+
+// GetWaitlist devuelve la lista de espera en orden de llegada
+func (r *ClassRepository) GetWaitlist(classID uint) ([]models.User, error) {
+	var students []models.User
+	err := db.GetDB().
+		Joins("JOIN class_students ON users.id = class_students.student_id").
+		Where("class_students.class_id = ? AND class_students.status = ?", classID, "WAITLISTED").
+		Order("class_students.enrolled_at ASC, class_students.student_id ASC").
+		Find(&students).Error
+	return students, err
+}
+
+// RequestEnrollment registra una solicitud de inscripción pendiente de aprobación
+func (r *ClassRepository) RequestEnrollment(classID, studentID uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(
+			"INSERT INTO class_students (class_id, student_id, status, enrolled_at) VALUES (?, ?, 'PENDING', now()) ON CONFLICT DO NOTHING",
+			classID, studentID,
+		)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return appendEnrollmentChange(tx, classID, studentID, "", "PENDING")
+	})
 }
 
-func (r *ClassRepository) AddStudent(classID, studentID uint) error {
-	return db.GetDB().Exec(
-		"INSERT INTO class_students (class_id, student_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
-		classID, studentID,
-	).Error
+func (r *ClassRepository) GetEnrollment(classID, studentID uint) (*models.ClassStudent, error) {
+	var cs models.ClassStudent
+	err := db.GetDB().Where("class_id = ? AND student_id = ?", classID, studentID).First(&cs).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cs, nil
 }
 
-func (r *ClassRepository) RemoveStudent(classID, studentID uint) error {
-	return db.GetDB().Exec(
-		"DELETE FROM class_students WHERE class_id = ? AND student_id = ?",
-		classID, studentID,
-	).Error
+func (r *ClassRepository) GetPendingStudents(classID uint) ([]models.User, error) {
+	var students []models.User
+	err := db.GetDB().
+		Joins("JOIN class_students ON users.id = class_students.student_id").
+		Where("class_students.class_id = ? AND class_students.status = ?", classID, "PENDING").
+		Find(&students).Error
+	return students, err
+}
+
+func (r *ClassRepository) FindByJoinCode(code string) (*models.Class, error) {
+	var class models.Class
+	if err := db.GetDB().Preload("Term").Where("join_code = ?", code).First(&class).Error; err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// SetJoinSettings actualiza sólo la configuración de auto-inscripción
+func (r *ClassRepository) SetJoinSettings(classID uint, code *string, enabled, requiresApproval bool) error {
+	return db.GetDB().Model(&models.Class{}).Where("id = ?", classID).Updates(map[string]interface{}{
+		"join_code":              code,
+		"join_enabled":           enabled,
+		"join_requires_approval": requiresApproval,
+	}).Error
+}
+
+// RemoveStudent da de baja al estudiante. Si ocupaba un cupo, la lista de
+// espera avanza mientras haya lugar según capacity (0 = sin límite); devuelve
+// los ids promovidos.
+func (r *ClassRepository) RemoveStudent(classID, studentID uint, capacity int) ([]uint, error) {
+	var promoted []uint
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		var class models.Class
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error; err != nil {
+			return err
+		}
+		var existing models.ClassStudent
+		if err := tx.Where("class_id = ? AND student_id = ?", classID, studentID).First(&existing).Error; err != nil {
+			return nil
+		}
+		if err := tx.Exec(
+			"DELETE FROM class_students WHERE class_id = ? AND student_id = ?",
+			classID, studentID,
+		).Error; err != nil {
+			return err
+		}
+		if err := appendEnrollmentChange(tx, classID, studentID, existing.Status, ""); err != nil {
+			return err
+		}
+		if existing.Status != "ENROLLED" {
+			return nil
+		}
+		var err error
+		promoted, err = promoteWaitlisted(tx, classID, capacity)
+		return err
+	})
+	return promoted, err
+}
+
+// promoteWaitlisted inscribe en orden de llegada a la lista de espera mientras
+// los inscritos no alcancen capacity (0 = sin límite). Bloquea la fila de la
+// clase para contar igual que AddStudent. Devuelve los ids promovidos.
+func promoteWaitlisted(tx *gorm.DB, classID uint, capacity int) ([]uint, error) {
+	var class models.Class
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error; err != nil {
+		return nil, err
+	}
+	var enrolled int64
+	if err := tx.Model(&models.ClassStudent{}).
+		Where("class_id = ? AND status = ?", classID, "ENROLLED").
+		Count(&enrolled).Error; err != nil {
+		return nil, err
+	}
+	var promoted []uint
+	for capacity == 0 || enrolled < int64(capacity) {
+		var next models.ClassStudent
+		err := tx.Where("class_id = ? AND status = ?", classID, "WAITLISTED").
+			Order("enrolled_at ASC, student_id ASC").
+			First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = tx.Model(&models.ClassStudent{}).
+			Where("class_id = ? AND student_id = ?", classID, next.StudentID).
+			Updates(map[string]interface{}{"status": "ENROLLED", "enrolled_at": time.Now()}).Error
+		if err != nil {
+			return nil, err
+		}
+		if err := appendEnrollmentChange(tx, classID, next.StudentID, "WAITLISTED", "ENROLLED"); err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, next.StudentID)
+		enrolled++
+	}
+	return promoted, nil
 }
 
 func (r *ClassRepository) GetStudents(classID uint) ([]models.User, error) {
 	var students []models.User
 	err := db.GetDB().
 		Joins("JOIN class_students ON users.id = class_students.student_id").
-		Where("class_students.class_id = ?", classID).
+		Where("class_students.class_id = ? AND class_students.status = ?", classID, "ENROLLED").
 		Find(&students).Error
 	return students, err
 }
 
-func (r *ClassRepository) FindClassesByStudentID(studentID uint) ([]models.Class, error) {
+func (r *ClassRepository) FindClassesByStudentID(studentID, termID uint) ([]models.Class, error) {
 	var classes []models.Class
-	err := db.GetDB().
+	q := db.GetDB().
 		Joins("JOIN class_students ON classes.id = class_students.class_id").
-		Where("class_students.student_id = ?", studentID).
-		Preload("Professor").
-		Find(&classes).Error
+		Where("class_students.student_id = ? AND class_students.status = ?", studentID, "ENROLLED")
+	if termID != 0 {
+		q = q.Where("classes.term_id = ?", termID)
+	}
+	err := q.Preload("Professor").Find(&classes).Error
+	return classes, err
+}
+
+type RosterEntry struct {
+	StudentID  uint      `json:"student_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Status     string    `json:"status"`
+	EnrolledAt time.Time `json:"enrolled_at"`
+}
+
+// GetRoster devuelve inscriptos y lista de espera con su fecha de inscripción
+func (r *ClassRepository) GetRoster(classID uint) ([]RosterEntry, error) {
+	var out []RosterEntry
+	err := db.GetDB().Table("class_students cs").
+		Select("cs.student_id, u.name, u.email, cs.status, cs.enrolled_at").
+		Joins("JOIN users u ON u.id = cs.student_id AND u.deleted_at IS NULL").
+		Where("cs.class_id = ? AND cs.status IN ?", classID, []string{"ENROLLED", "WAITLISTED"}).
+		Order("cs.status ASC, cs.enrolled_at ASC, u.name ASC").
+		Scan(&out).Error
+	return out, err
+}
+
+func (r *ClassRepository) FindByStaffUserID(userID, termID uint) ([]models.Class, error) {
+	var classes []models.Class
+	q := db.GetDB().Where("id IN (SELECT class_id FROM class_staffs WHERE user_id = ?)", userID)
+	if termID != 0 {
+		q = q.Where("term_id = ?", termID)
+	}
+	err := q.Preload("Professor").Find(&classes).Error
 	return classes, err
 }
+
+func (r *ClassRepository) GetStaffMember(classID, userID uint) (*models.ClassStaff, error) {
+	var m models.ClassStaff
+	if err := db.GetDB().Where("class_id = ? AND user_id = ?", classID, userID).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *ClassRepository) GetStaff(classID uint) ([]models.ClassStaff, error) {
+	var list []models.ClassStaff
+	err := db.GetDB().Where("class_id = ?", classID).Preload("User").Order("created_at ASC").Find(&list).Error
+	return list, err
+}
+
+// FindStaffByClassIDs devuelve los docentes adicionales de las clases
+func (r *ClassRepository) FindStaffByClassIDs(classIDs []uint) ([]models.ClassStaff, error) {
+	var list []models.ClassStaff
+	if len(classIDs) == 0 {
+		return list, nil
+	}
+	err := db.GetDB().Where("class_id IN ?", classIDs).Find(&list).Error
+	return list, err
+}
+
+// SetStaffMember agrega al usuario al equipo docente o actualiza su rol
+func (r *ClassRepository) SetStaffMember(m *models.ClassStaff) error {
+	return db.GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "class_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Omit("User").Create(m).Error
+}
+
+func (r *ClassRepository) RemoveStaffMember(classID, userID uint) error {
+	return db.GetDB().Where("class_id = ? AND user_id = ?", classID, userID).Delete(&models.ClassStaff{}).Error
+}
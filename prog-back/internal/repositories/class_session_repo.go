@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type ClassSessionRepository struct{}
+
+func NewClassSessionRepository() *ClassSessionRepository { return &ClassSessionRepository{} }
+
+func (r *ClassSessionRepository) GetByID(id uint) (*models.ClassSession, error) {
+	var s models.ClassSession
+	if err := db.GetDB().Preload("Room").First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ClassSessionRepository) FindByClassID(classID uint) ([]models.ClassSession, error) {
+	var list []models.ClassSession
+	err := db.GetDB().Where("class_id = ?", classID).Preload("Room").
+		Order("weekday ASC, start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// CreateWithReservations guarda la sesión y sus reservas en una única transacción
+func (r *ClassSessionRepository) CreateWithReservations(s *models.ClassSession, resvs []models.Reservation) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(s).Error; err != nil {
+			return err
+		}
+		return createSessionReservations(tx, s.ID, resvs)
+	})
+}
+
+// UpdateWithReservations actualiza la sesión, cancela sus reservas futuras y
+// crea las nuevas en una única transacción
+func (r *ClassSessionRepository) UpdateWithReservations(s *models.ClassSession, from time.Time, resvs []models.Reservation) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Room").Save(s).Error; err != nil {
+			return err
+		}
+		if err := cancelFutureSessionReservations(tx, s.ID, from); err != nil {
+			return err
+		}
+		return createSessionReservations(tx, s.ID, resvs)
+	})
+}
+
+// DeleteWithReservations elimina la sesión y cancela sus reservas futuras
+func (r *ClassSessionRepository) DeleteWithReservations(id uint, from time.Time) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := cancelFutureSessionReservations(tx, id, from); err != nil {
+			return err
+		}
+		return tx.Delete(&models.ClassSession{}, id).Error
+	})
+}
+
+func createSessionReservations(tx *gorm.DB, sessionID uint, resvs []models.Reservation) error {
+	if len(resvs) == 0 {
+		return nil
+	}
+	for i := range resvs {
+		resvs[i].SessionID = &sessionID
+		if err := lockRooms(tx, resvs[i].RoomID); err != nil {
+			return err
+		}
+		if err := checkRoomFree(tx, &resvs[i]); err != nil {
+			return err
+		}
+	}
+	if err := tx.Create(&resvs).Error; err != nil {
+		return err
+	}
+	return appendReservationEvents(tx, models.EventReservationCreated, resvs)
+}
+
+func cancelFutureSessionReservations(tx *gorm.DB, sessionID uint, from time.Time) error {
+	_, err := setReservationStatus(tx, "CANCELLED", models.EventReservationCancelled,
+		"session_id = ? AND status = ? AND start_time >= ?", sessionID, "ACTIVE", from)
+	return err
+}
+
+func (r *ClassSessionRepository) FindByClassIDs(classIDs []uint) ([]models.ClassSession, error) {
+	var list []models.ClassSession
+	if len(classIDs) == 0 {
+		return list, nil
+	}
+	err := db.GetDB().Where("class_id IN ?", classIDs).Find(&list).Error
+	return list, err
+}
+
+// CreateManyWithReservations guarda varias sesiones con sus reservas en una
+// única transacción (resvs[i] corresponde a sessions[i])
+func (r *ClassSessionRepository) CreateManyWithReservations(sessions []models.ClassSession, resvs [][]models.Reservation) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Todas las aulas de una vez y en orden, para no interbloquearse
+		var roomIDs []uint
+		for i := range resvs {
+			for j := range resvs[i] {
+				roomIDs = append(roomIDs, resvs[i][j].RoomID)
+			}
+		}
+		if err := lockRooms(tx, roomIDs...); err != nil {
+			return err
+		}
+		for i := range sessions {
+			if err := tx.Create(&sessions[i]).Error; err != nil {
+				return err
+			}
+			if err := createSessionReservations(tx, sessions[i].ID, resvs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type ExamRepository struct{}
+
+func NewExamRepository() *ExamRepository { return &ExamRepository{} }
+
+// CreateWithReservations guarda el examen, una reserva por aula, los vigilantes
+// y los asientos en una única transacción. resvs va en el mismo orden que rooms.
+// Las aulas y los vigilantes se vuelven a verificar bajo sus locks.
+func (r *ExamRepository) CreateWithReservations(exam *models.Exam, rooms []models.ExamRoom, resvs []models.Reservation) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rooms").Create(exam).Error; err != nil {
+			return err
+		}
+		roomIDs := make([]uint, len(resvs))
+		for i := range resvs {
+			roomIDs[i] = resvs[i].RoomID
+		}
+		if err := lockRooms(tx, roomIDs...); err != nil {
+			return err
+		}
+		for i := range resvs {
+			if err := checkRoomFree(tx, &resvs[i]); err != nil {
+				return err
+			}
+		}
+		var invigilators []uint
+		for i := range rooms {
+			for _, inv := range rooms[i].Invigilators {
+				invigilators = append(invigilators, inv.UserID)
+			}
+		}
+		if err := advisoryLock(tx, invigilatorLockSpace, invigilators); err != nil {
+			return err
+		}
+		for _, uid := range invigilators {
+			busy, err := invigilatorBusy(tx, uid, exam.StartTime, exam.EndTime)
+			if err != nil {
+				return err
+			}
+			if busy {
+				return fmt.Errorf("%w (user %d)", ErrInvigilatorBusy, uid)
+			}
+		}
+		for i := range rooms {
+			resvs[i].ExamID = &exam.ID
+			if err := tx.Create(&resvs[i]).Error; err != nil {
+				return err
+			}
+			if err := appendOutbox(tx, "reservation", resvs[i].ID, models.EventReservationCreated, &resvs[i]); err != nil {
+				return err
+			}
+			rooms[i].ExamID = exam.ID
+			rooms[i].ReservationID = resvs[i].ID
+			if err := tx.Omit("Room", "Invigilators", "Seats").Create(&rooms[i]).Error; err != nil {
+				return err
+			}
+			for j := range rooms[i].Invigilators {
+				rooms[i].Invigilators[j].ExamRoomID = rooms[i].ID
+				rooms[i].Invigilators[j].ExamID = exam.ID
+			}
+			for j := range rooms[i].Seats {
+				rooms[i].Seats[j].ExamRoomID = rooms[i].ID
+			}
+			if len(rooms[i].Invigilators) > 0 {
+				if err := tx.Omit("User").Create(&rooms[i].Invigilators).Error; err != nil {
+					return err
+				}
+			}
+			if len(rooms[i].Seats) > 0 {
+				if err := tx.Omit("Student").Create(&rooms[i].Seats).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ExamRepository) FindByID(id uint) (*models.Exam, error) {
+	var exam models.Exam
+	err := db.GetDB().
+		Preload("Class").
+		Preload("Rooms.Room").
+		Preload("Rooms.Invigilators.User").
+		Preload("Rooms.Seats", func(tx *gorm.DB) *gorm.DB { return tx.Order("seat_number ASC") }).
+		Preload("Rooms.Seats.Student").
+		First(&exam, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &exam, nil
+}
+
+func (r *ExamRepository) FindByClassID(classID uint) ([]models.Exam, error) {
+	var list []models.Exam
+	err := db.GetDB().Where("class_id = ?", classID).
+		Preload("Rooms.Room").
+		Order("start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// Espacio de los advisory locks por vigilante: pg_advisory_xact_lock(espacio, user_id)
+const invigilatorLockSpace = 0x696e7667 // "invg"
+
+// ErrInvigilatorBusy indica que un vigilante se ocupó entre la validación y el alta
+var ErrInvigilatorBusy = errors.New("invigilator already booked at that time")
+
+// InvigilatorBusy indica si el usuario ya vigila otro examen, tiene una
+// reserva activa o dicta (profesor, co-docente o ayudante) una clase con
+// reserva que se solapa con el intervalo
+func (r *ExamRepository) InvigilatorBusy(userID uint, start, end time.Time) (bool, error) {
+	return invigilatorBusy(db.GetDB(), userID, start, end)
+}
+
+func invigilatorBusy(tx *gorm.DB, userID uint, start, end time.Time) (bool, error) {
+	var count int64
+	err := tx.Model(&models.ExamInvigilator{}).
+		Joins("JOIN exams ON exams.id = exam_invigilators.exam_id").
+		Where("exam_invigilators.user_id = ? AND exams.status = ?", userID, "ACTIVE").
+		Where("exams.start_time < ? AND exams.end_time > ?", end, start).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+	// Los exámenes ya se contaron por sus vigilantes
+	err = personOverlaps(tx.Session(&gorm.Session{NewDB: true}), []uint{userID},
+		[]string{"OWNER", "CO_TEACHER", "TA"}, start, end).
+		Where("kind <> ?", "EXAM").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Cancel marca el examen como cancelado y libera sus reservas
+func (r *ExamRepository) Cancel(id uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Exam{}).Where("id = ?", id).Update("status", "CANCELLED").Error; err != nil {
+			return err
+		}
+		_, err := setReservationStatus(tx, "CANCELLED", models.EventReservationCancelled,
+			"exam_id = ? AND status = ?", id, "ACTIVE")
+		return err
+	})
+}
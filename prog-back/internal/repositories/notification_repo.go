@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct{}
+
+func NewNotificationRepository() *NotificationRepository { return &NotificationRepository{} }
+
+func (r *NotificationRepository) CreateDeliveries(list []models.NotificationDelivery) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return db.GetDB().Create(&list).Error
+}
+
+// ClaimDueDeliveries toma hasta limit envíos pendientes y vencidos y los
+// reserva durante lease. SKIP LOCKED evita que dos réplicas tomen el mismo
+// envío; si el proceso muere, el envío vuelve a quedar disponible al vencer.
+func (r *NotificationRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+	var list []models.NotificationDelivery
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", "PENDING", now).
+			Order("id ASC").Limit(limit).Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(list))
+		for i, d := range list {
+			ids[i] = d.ID
+		}
+		return tx.Model(&models.NotificationDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return list, err
+}
+
+func (r *NotificationRepository) MarkDeliverySent(id uint, at time.Time) error {
+	return db.GetDB().Model(&models.NotificationDelivery{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     "SENT",
+		"sent_at":    at,
+		"last_error": "",
+	}).Error
+}
+
+// MarkDeliveryFailed registra el intento fallido. Con next == nil el envío
+// queda FAILED definitivamente.
+func (r *NotificationRepository) MarkDeliveryFailed(id uint, attempts int, next *time.Time, lastErr string) error {
+	updates := map[string]interface{}{"attempts": attempts, "last_error": lastErr}
+	if next != nil {
+		updates["next_attempt_at"] = *next
+	} else {
+		updates["status"] = "FAILED"
+	}
+	return db.GetDB().Model(&models.NotificationDelivery{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *NotificationRepository) CreateNotification(n *models.Notification) error {
+	return db.GetDB().Create(n).Error
+}
+
+func (r *NotificationRepository) ListForUser(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var list []models.Notification
+	q := db.GetDB().Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	err := q.Order("created_at DESC, id DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+func (r *NotificationRepository) UnreadCount(userID uint) (int64, error) {
+	var count int64
+	err := db.GetDB().Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marca como leída una notificación del usuario; devuelve false si no existe
+func (r *NotificationRepository) MarkRead(id, userID uint, at time.Time) (bool, error) {
+	var n models.Notification
+	if err := db.GetDB().Where("id = ? AND user_id = ?", id, userID).First(&n).Error; err != nil {
+		return false, nil
+	}
+	if n.ReadAt != nil {
+		return true, nil
+	}
+	return true, db.GetDB().Model(&n).Update("read_at", at).Error
+}
+
+func (r *NotificationRepository) MarkAllRead(userID uint, at time.Time) error {
+	return db.GetDB().Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).Update("read_at", at).Error
+}
+
+func (r *NotificationRepository) GetPreferences(userID uint) ([]models.NotificationPreference, error) {
+	var list []models.NotificationPreference
+	err := db.GetDB().Where("user_id = ?", userID).Find(&list).Error
+	return list, err
+}
+
+func (r *NotificationRepository) SavePreferences(prefs []models.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return db.GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "in_app"}),
+	}).Create(&prefs).Error
+}
+
+// PreferencesForEvent devuelve las preferencias guardadas de los usuarios para un evento
+func (r *NotificationRepository) PreferencesForEvent(userIDs []uint, event string) (map[uint]models.NotificationPreference, error) {
+	var list []models.NotificationPreference
+	out := make(map[uint]models.NotificationPreference)
+	if len(userIDs) == 0 {
+		return out, nil
+	}
+	if err := db.GetDB().Where("user_id IN ? AND event = ?", userIDs, event).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range list {
+		out[p.UserID] = p
+	}
+	return out, nil
+}
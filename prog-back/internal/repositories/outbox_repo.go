@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"encoding/json"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Clave del advisory lock que garantiza un único relay activo entre réplicas
+const outboxRelayLockKey int64 = 0x6f7574626f78
+
+type OutboxRepository struct{}
+
+func NewOutboxRepository() *OutboxRepository { return &OutboxRepository{} }
+
+// EnrollmentChange es el payload de class.enrollment.changed ("" = sin inscripción)
+type EnrollmentChange struct {
+	ClassID        uint   `json:"class_id"`
+	StudentID      uint   `json:"student_id"`
+	PreviousStatus string `json:"previous_status"`
+	Status         string `json:"status"`
+}
+
+// appendOutbox registra un evento dentro de la transacción del cambio
+func appendOutbox(tx *gorm.DB, aggregateType string, aggregateID uint, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Event:         event,
+		Payload:       body,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+func appendReservationEvents(tx *gorm.DB, event string, resvs []models.Reservation) error {
+	for i := range resvs {
+		if err := appendOutbox(tx, "reservation", resvs[i].ID, event, &resvs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendEnrollmentChange(tx *gorm.DB, classID, studentID uint, previous, status string) error {
+	if previous == status {
+		return nil
+	}
+	return appendOutbox(tx, "class", classID, models.EventEnrollmentChanged, EnrollmentChange{
+		ClassID:        classID,
+		StudentID:      studentID,
+		PreviousStatus: previous,
+		Status:         status,
+	})
+}
+
+// setReservationStatus cambia el estado de las reservas que cumplen la
+// condición y registra un evento por cada una. Devuelve cuántas cambió.
+func setReservationStatus(tx *gorm.DB, status, event string, query string, args ...interface{}) (int64, error) {
+	var ids []uint
+	if err := tx.Model(&models.Reservation{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(query, args...).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := tx.Model(&models.Reservation{}).Where("id IN ?", ids).Update("status", status).Error; err != nil {
+		return 0, err
+	}
+	var list []models.Reservation
+	if err := tx.Where("id IN ?", ids).Order("id ASC").Find(&list).Error; err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), appendReservationEvents(tx, event, list)
+}
+
+// ProcessPending toma el lock del relay y entrega a handle hasta limit eventos
+// pendientes, en orden de id. Si un evento falla o está en espera de
+// reintento, los siguientes del mismo agregado se posponen para conservar el
+// orden por agregado. Devuelve cuántos se publicaron (0 si otra réplica tiene el lock).
+func (r *OutboxRepository) ProcessPending(limit int, backoff func(attempts int) time.Duration, handle func(e *models.OutboxEvent) error) (int, error) {
+	published := 0
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		// Se excluyen los eventos detrás de uno del mismo agregado que espera reintento
+		now := time.Now()
+		var pending []models.OutboxEvent
+		if err := tx.Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Where("NOT EXISTS (SELECT 1 FROM outbox_events o WHERE o.published_at IS NULL AND o.next_attempt_at > ? "+
+				"AND o.aggregate_type = outbox_events.aggregate_type AND o.aggregate_id = outbox_events.aggregate_id "+
+				"AND o.id < outbox_events.id)", now).
+			Order("id ASC").Limit(limit).Find(&pending).Error; err != nil {
+			return err
+		}
+		type aggregate struct {
+			kind string
+			id   uint
+		}
+		blocked := map[aggregate]bool{}
+		for i := range pending {
+			e := &pending[i]
+			key := aggregate{e.AggregateType, e.AggregateID}
+			if blocked[key] {
+				continue
+			}
+			if err := handle(e); err != nil {
+				blocked[key] = true
+				if err := tx.Model(e).Updates(map[string]interface{}{
+					"attempts":        e.Attempts + 1,
+					"last_error":      err.Error(),
+					"next_attempt_at": time.Now().Add(backoff(e.Attempts)),
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(e).Update("published_at", time.Now()).Error; err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	return published, err
+}
+
+// PrunePublished borra los eventos publicados antes del corte
+func (r *OutboxRepository) PrunePublished(before time.Time) (int64, error) {
+	res := db.GetDB().Where("published_at < ?", before).Delete(&models.OutboxEvent{})
+	return res.RowsAffected, res.Error
+}
+
+// FindAfter devuelve, en orden de id, hasta limit eventos con id mayor a
+// afterID. upToID (0 = sin tope) acota el resultado y aggregateTypes (vacío =
+// todos) filtra por tipo de agregado.
+func (r *OutboxRepository) FindAfter(afterID, upToID uint, aggregateTypes []string, limit int) ([]models.OutboxEvent, error) {
+	var list []models.OutboxEvent
+	q := db.GetDB().Where("id > ?", afterID)
+	if upToID != 0 {
+		q = q.Where("id <= ?", upToID)
+	}
+	if len(aggregateTypes) > 0 {
+		q = q.Where("aggregate_type IN ?", aggregateTypes)
+	}
+	err := q.Order("id ASC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// FindByIDs devuelve los eventos indicados que ya son visibles, en orden de id
+func (r *OutboxRepository) FindByIDs(ids []uint) ([]models.OutboxEvent, error) {
+	var list []models.OutboxEvent
+	if len(ids) == 0 {
+		return list, nil
+	}
+	err := db.GetDB().Where("id IN ?", ids).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+// LastID devuelve el id del último evento registrado (0 si no hay)
+func (r *OutboxRepository) LastID() (uint, error) {
+	var id uint
+	err := db.GetDB().Model(&models.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
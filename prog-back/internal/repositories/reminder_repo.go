@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository struct{}
+
+func NewReminderRepository() *ReminderRepository { return &ReminderRepository{} }
+
+// Claim reserva la clave; devuelve false si otra réplica (o una ejecución
+// anterior) ya la había reclamado
+func (r *ReminderRepository) Claim(key string) (bool, error) {
+	res := db.GetDB().Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ReminderClaim{Key: key, CreatedAt: time.Now()})
+	return res.RowsAffected == 1, res.Error
+}
+
+// Release libera una clave reclamada cuyo envío no llegó a encolarse, para
+// que la próxima pasada lo reintente
+func (r *ReminderRepository) Release(key string) error {
+	return db.GetDB().Where("key = ?", key).Delete(&models.ReminderClaim{}).Error
+}
+
+// DeleteOlderThan limpia las claves viejas que ya no pueden volver a usarse
+func (r *ReminderRepository) DeleteOlderThan(t time.Time) error {
+	return db.GetDB().Where("created_at < ?", t).Delete(&models.ReminderClaim{}).Error
+}
@@ -1,18 +1,93 @@
 package repositories
 
 import (
+	"errors"
+	"sort"
 	"time"
 
 	"programcion-backend/internal/models"
 	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReservationRepository struct{}
 
 func NewReservationRepository() *ReservationRepository { return &ReservationRepository{} }
 
+// ErrSlotTaken indica que el horario se ocupó entre la validación y el alta
+var ErrSlotTaken = errors.New("time slot not available (overlap)")
+
+// ErrHoldNotActive indica que el bloqueo venció o ya no está vigente
+var ErrHoldNotActive = errors.New("hold expired or not found")
+
+// Espacio de los advisory locks por aula: pg_advisory_xact_lock(espacio, room_id)
+const roomLockSpace = 0x726f6f6d // "room"
+
+// lockRooms toma hasta el fin de la transacción el lock de escritura de cada
+// aula. Quien verifica solapamientos y después inserta debe hacerlo bajo este
+// lock; se toman en orden para no interbloquearse.
+func lockRooms(tx *gorm.DB, roomIDs ...uint) error {
+	return advisoryLock(tx, roomLockSpace, roomIDs)
+}
+
+// advisoryLock toma pg_advisory_xact_lock(space, id) de cada id, en orden y
+// sin repetir
+func advisoryLock(tx *gorm.DB, space int, ids []uint) error {
+	ids = append([]uint(nil), ids...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i, id := range ids {
+		if i > 0 && ids[i-1] == id {
+			continue
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", space, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoomFree devuelve ErrSlotTaken si la reserva choca con otra del aula
+// (sin contar la propia ni las de su misma sesión). Debe llamarse bajo lockRooms.
+func checkRoomFree(tx *gorm.DB, resv *models.Reservation) error {
+	q := overlappingLive(tx, resv.StartTime, resv.EndTime).Where("room_id = ?", resv.RoomID)
+	if resv.ID != 0 {
+		q = q.Where("id <> ?", resv.ID)
+	}
+	if resv.SessionID != nil {
+		q = q.Where("session_id IS NULL OR session_id <> ?", *resv.SessionID)
+	}
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlotTaken
+	}
+	return nil
+}
+
+// Create guarda la reserva si el aula sigue libre: la verificación y el alta
+// corren en la misma transacción bajo el lock del aula. Las activas registran
+// reservation.created en el outbox; los bloqueos temporales no se publican
+// hasta confirmarse.
 func (r *ReservationRepository) Create(resv *models.Reservation) error {
-	return db.GetDB().Create(resv).Error
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := lockRooms(tx, resv.RoomID); err != nil {
+			return err
+		}
+		if err := checkRoomFree(tx, resv); err != nil {
+			return err
+		}
+		if err := tx.Create(resv).Error; err != nil {
+			return err
+		}
+		if resv.Status != "ACTIVE" {
+			return nil
+		}
+		return appendOutbox(tx, "reservation", resv.ID, models.EventReservationCreated, resv)
+	})
 }
 
 func (r *ReservationRepository) GetByID(id uint) (*models.Reservation, error) {
@@ -43,16 +118,291 @@ func (r *ReservationRepository) List(filter map[string]interface{}, from, to *ti
 
 func (r *ReservationRepository) HasOverlapping(roomID uint, start, end time.Time) (bool, error) {
 	var count int64
-	db.GetDB().Model(&models.Reservation{}).
-		Where("room_id = ? AND status = ? AND start_time < ? AND end_time > ?", roomID, "ACTIVE", end, start).
-		Count(&count)
-	return count > 0, nil
+	err := overlappingLive(db.GetDB(), start, end).Where("room_id = ?", roomID).Count(&count).Error
+	return count > 0, err
+}
+
+// FindOverlapping devuelve las reservas que ocupan el aula en el intervalo,
+// ignorando las generadas por la sesión indicada (0 = ninguna)
+func (r *ReservationRepository) FindOverlapping(roomID uint, start, end time.Time, excludeSessionID uint) ([]models.Reservation, error) {
+	var list []models.Reservation
+	q := overlappingLive(db.GetDB(), start, end).Where("room_id = ?", roomID)
+	if excludeSessionID != 0 {
+		q = q.Where("session_id IS NULL OR session_id <> ?", excludeSessionID)
+	}
+	err := q.Order("start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// FindRoomAgenda devuelve las reservas activas de las aulas que siguen en
+// curso o empiezan antes de to, en orden de inicio
+func (r *ReservationRepository) FindRoomAgenda(roomIDs []uint, from, to time.Time) ([]models.Reservation, error) {
+	var list []models.Reservation
+	if len(roomIDs) == 0 {
+		return list, nil
+	}
+	err := db.GetDB().Preload("Class").
+		Where("room_id IN ? AND status = ? AND end_time > ? AND start_time < ?", roomIDs, "ACTIVE", from, to).
+		Order("start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// FindActiveBetween devuelve las reservas activas que se solapan con el
+// intervalo, de cualquier aula
+func (r *ReservationRepository) FindActiveBetween(from, to time.Time) ([]models.Reservation, error) {
+	var list []models.Reservation
+	err := db.GetDB().
+		Where("status = ? AND start_time < ? AND end_time > ?", "ACTIVE", to, from).
+		Order("start_time ASC").Find(&list).Error
+	return list, err
 }
 
 func (r *ReservationRepository) Delete(id uint) error {
 	return db.GetDB().Delete(&models.Reservation{}, id).Error
 }
 
-func (r *ReservationRepository) Update(resv *models.Reservation) error {
-	return db.GetDB().Save(resv).Error
+// Update guarda la reserva y, si event no es vacío, registra el evento en el
+// outbox dentro de la misma transacción
+func (r *ReservationRepository) Update(resv *models.Reservation, event string) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(resv).Error; err != nil {
+			return err
+		}
+		if event == "" {
+			return nil
+		}
+		return appendOutbox(tx, "reservation", resv.ID, event, resv)
+	})
+}
+
+// FindCheckinCandidate busca la reserva activa del usuario en el aula cuyo
+// horario abarca el instante dado (incluyendo el margen previo al inicio)
+func (r *ReservationRepository) FindCheckinCandidate(roomID, userID uint, at time.Time, early time.Duration) (*models.Reservation, error) {
+	var rsv models.Reservation
+	err := db.GetDB().
+		Where("room_id = ? AND user_id = ? AND status = ? AND checked_in_at IS NULL", roomID, userID, "ACTIVE").
+		Where("start_time <= ? AND end_time > ?", at.Add(early), at).
+		Order("start_time ASC").
+		First(&rsv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rsv, nil
+}
+
+// MarkNoShows marca como NO_SHOW las reservas puntuales activas y sin
+// check-in que siguen en curso y comenzaron entre since y cutoff, liberando
+// así el resto del horario. Las sesiones de clase y los exámenes no se tocan:
+// su asistencia se lleva aparte.
+func (r *ReservationRepository) MarkNoShows(since, cutoff, now time.Time) (int64, error) {
+	var count int64
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		count, err = setReservationStatus(tx, "NO_SHOW", models.EventReservationNoShow,
+			"status = ? AND checked_in_at IS NULL AND session_id IS NULL AND exam_id IS NULL AND start_time > ? AND start_time <= ? AND end_time > ?",
+			"ACTIVE", since, cutoff, now)
+		return err
+	})
+	return count, err
+}
+
+type NoShowCount struct {
+	UserID    uint   `json:"user_id"`
+	UserName  string `json:"user_name"`
+	UserEmail string `json:"user_email"`
+	NoShows   int64  `json:"no_shows"`
+}
+
+func (r *ReservationRepository) CountNoShowsByUser(from, to *time.Time) ([]NoShowCount, error) {
+	var out []NoShowCount
+	q := db.GetDB().Table("reservations r").
+		Select("r.user_id, u.name as user_name, u.email as user_email, COUNT(*) as no_shows").
+		Joins("JOIN users u ON u.id = r.user_id").
+		Where("r.status = ?", "NO_SHOW")
+	if from != nil {
+		q = q.Where("r.start_time >= ?", *from)
+	}
+	if to != nil {
+		q = q.Where("r.start_time <= ?", *to)
+	}
+	err := q.Group("r.user_id, u.name, u.email").Order("no_shows DESC").Scan(&out).Error
+	return out, err
+}
+
+// ConfirmHold convierte el bloqueo en reserva activa. El vencimiento y el
+// solapamiento se vuelven a verificar bajo el lock del aula.
+func (r *ReservationRepository) ConfirmHold(id uint, now time.Time) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		var resv models.Reservation
+		if err := tx.First(&resv, id).Error; err != nil {
+			return ErrHoldNotActive
+		}
+		if err := lockRooms(tx, resv.RoomID); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&resv, id).Error; err != nil {
+			return err
+		}
+		if resv.Status != "HELD" || resv.HoldExpiresAt == nil || !now.Before(*resv.HoldExpiresAt) {
+			return ErrHoldNotActive
+		}
+		if err := checkRoomFree(tx, &resv); err != nil {
+			return err
+		}
+		resv.Status = "ACTIVE"
+		resv.HoldExpiresAt = nil
+		if err := tx.Model(&resv).Updates(map[string]interface{}{"status": "ACTIVE", "hold_expires_at": nil}).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "reservation", resv.ID, models.EventReservationCreated, &resv)
+	})
+}
+
+// ExpireHolds marca como EXPIRED los bloqueos temporales vencidos y registra
+// reservation.expired por cada uno
+func (r *ReservationRepository) ExpireHolds(now time.Time) (int64, error) {
+	var count int64
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		count, err = setReservationStatus(tx, "EXPIRED", models.EventReservationExpired,
+			"status = ? AND hold_expires_at <= ?", "HELD", now)
+		return err
+	})
+	return count, err
+}
+
+// FindStudentSchedule devuelve las reservas activas de las clases indicadas en
+// el intervalo. De los exámenes sólo incluye el aula donde el estudiante tiene asiento.
+func (r *ReservationRepository) FindStudentSchedule(studentID uint, classIDs []uint, from, to time.Time) ([]models.Reservation, error) {
+	var list []models.Reservation
+	if len(classIDs) == 0 {
+		return list, nil
+	}
+	err := db.GetDB().
+		Preload("Room.Building").Preload("Class").
+		Where("class_id IN ? AND status = ?", classIDs, "ACTIVE").
+		Where("start_time < ? AND end_time > ?", to, from).
+		Where("kind <> ? OR id IN (?)", "EXAM",
+			db.GetDB().Table("exam_rooms").Select("exam_rooms.reservation_id").
+				Joins("JOIN exam_seats ON exam_seats.exam_room_id = exam_rooms.id").
+				Where("exam_seats.student_id = ?", studentID)).
+		Order("start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// FindPersonOverlaps devuelve las reservas vigentes del intervalo hechas por
+// alguno de los usuarios o de clases que dictan (dueño o co-docente),
+// ignorando las generadas por la sesión indicada (0 = ninguna)
+func (r *ReservationRepository) FindPersonOverlaps(userIDs []uint, start, end time.Time, excludeSessionID uint) ([]models.Reservation, error) {
+	var list []models.Reservation
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	q := personOverlaps(db.GetDB(), userIDs, []string{"OWNER", "CO_TEACHER"}, start, end).
+		Preload("Room").Preload("User").Preload("Class.Staff")
+	if excludeSessionID != 0 {
+		q = q.Where("session_id IS NULL OR session_id <> ?", excludeSessionID)
+	}
+	err := q.Order("start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// personOverlaps filtra las reservas vigentes del intervalo hechas por alguno
+// de los usuarios o de clases en las que tienen alguno de los roles de staff
+// (el profesor de la clase cuenta siempre). Omite las de cartelería.
+func personOverlaps(tx *gorm.DB, userIDs []uint, staffRoles []string, start, end time.Time) *gorm.DB {
+	taught := tx.Session(&gorm.Session{NewDB: true}).Table("classes").Select("id").
+		Where("professor_id IN ?", userIDs)
+	staffed := tx.Session(&gorm.Session{NewDB: true}).Table("class_staffs").Select("class_id").
+		Where("user_id IN ? AND role IN ?", userIDs, staffRoles)
+	return overlappingLive(tx, start, end).
+		Where("user_id IN ? OR class_id IN (?) OR class_id IN (?)", userIDs, taught, staffed).
+		Where("signage_token_id IS NULL")
+}
+
+// FindStudentOverlaps devuelve las reservas vigentes del intervalo de otras
+// clases que comparten estudiantes inscritos con la clase indicada
+func (r *ReservationRepository) FindStudentOverlaps(classID uint, start, end time.Time) ([]models.Reservation, error) {
+	var list []models.Reservation
+	students := db.GetDB().Table("class_students").Select("student_id").
+		Where("class_id = ? AND status = ?", classID, "ENROLLED")
+	shared := db.GetDB().Table("class_students").Select("class_id").
+		Where("status = ? AND student_id IN (?)", "ENROLLED", students)
+	err := overlappingLive(db.GetDB(), start, end).
+		Preload("Room").Preload("Class").
+		Where("class_id <> ? AND class_id IN (?)", classID, shared).
+		Order("start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// overlappingLive filtra reservas activas o bloqueos vigentes que se solapan con el intervalo
+func overlappingLive(tx *gorm.DB, start, end time.Time) *gorm.DB {
+	return tx.Model(&models.Reservation{}).
+		Where("start_time < ? AND end_time > ?", end, start).
+		Where("status = ? OR (status = ? AND hold_expires_at > ?)", "ACTIVE", "HELD", time.Now())
+}
+
+// FindActiveStartingBetween devuelve las reservas activas que comienzan en el
+// intervalo, sin las instantáneas de cartelería (no tienen a quién avisar)
+func (r *ReservationRepository) FindActiveStartingBetween(from, to time.Time) ([]models.Reservation, error) {
+	var list []models.Reservation
+	err := db.GetDB().Preload("Room").
+		Where("status = ? AND start_time > ? AND start_time <= ?", "ACTIVE", from, to).
+		Where("signage_token_id IS NULL").
+		Order("user_id ASC, start_time ASC").Find(&list).Error
+	return list, err
+}
+
+// PublicReservationRow es una reserva con los datos que la vista pública
+// puede llegar a mostrar; el servicio decide qué campos expone
+type PublicReservationRow struct {
+	ID                 uint
+	RoomID             uint
+	RoomName           string
+	BuildingID         uint
+	BuildingName       string
+	ClassName          string
+	UserName           string
+	UserEmail          string
+	StartTime          time.Time
+	EndTime            time.Time
+	Purpose            string
+	EstimatedAttendees int
+	Private            bool
+}
+
+// PublicReservationQuery filtra la vista pública. La página sigue a la
+// reserva (AfterStart, AfterID) en orden de inicio e id.
+type PublicReservationQuery struct {
+	From       time.Time
+	To         time.Time
+	BuildingID uint
+	RoomID     uint
+	AfterStart *time.Time
+	AfterID    uint
+	Limit      int
+}
+
+func (r *ReservationRepository) FindPublic(q PublicReservationQuery) ([]PublicReservationRow, error) {
+	var rows []PublicReservationRow
+	tx := db.GetDB().Table("reservations r").
+		Select("r.id, r.room_id, rm.name AS room_name, rm.building_id, b.name AS building_name, "+
+			"COALESCE(c.name, '') AS class_name, COALESCE(u.name, '') AS user_name, COALESCE(u.email, '') AS user_email, "+
+			"r.start_time, r.end_time, r.purpose, r.estimated_attendees, r.private").
+		Joins("JOIN rooms rm ON rm.id = r.room_id").
+		Joins("JOIN buildings b ON b.id = rm.building_id").
+		Joins("LEFT JOIN classes c ON c.id = r.class_id").
+		Joins("LEFT JOIN users u ON u.id = r.user_id").
+		Where("r.status = ? AND r.end_time > ? AND r.start_time < ?", "ACTIVE", q.From, q.To)
+	if q.BuildingID != 0 {
+		tx = tx.Where("rm.building_id = ?", q.BuildingID)
+	}
+	if q.RoomID != 0 {
+		tx = tx.Where("r.room_id = ?", q.RoomID)
+	}
+	if q.AfterStart != nil {
+		tx = tx.Where("(r.start_time, r.id) > (?, ?)", *q.AfterStart, q.AfterID)
+	}
+	err := tx.Order("r.start_time ASC, r.id ASC").Limit(q.Limit).Scan(&rows).Error
+	return rows, err
 }
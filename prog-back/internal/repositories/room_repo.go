@@ -3,6 +3,8 @@ package repositories
 import (
 	"programcion-backend/internal/models"
 	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
 )
 
 type RoomRepository struct{}
@@ -19,6 +21,12 @@ func (r *RoomRepository) GetAll() ([]models.Room, error) {
 	return list, nil
 }
 
+func (r *RoomRepository) FindByBuildingID(buildingID uint) ([]models.Room, error) {
+	var rooms []models.Room
+	err := db.GetDB().Preload("Building").Where("building_id = ?", buildingID).Order("name ASC").Find(&rooms).Error
+	return rooms, err
+}
+
 func (r *RoomRepository) GetByID(id uint) (*models.Room, error) {
 	var m models.Room
 	if err := db.GetDB().Preload("Building").First(&m, id).Error; err != nil {
@@ -27,6 +35,22 @@ func (r *RoomRepository) GetByID(id uint) (*models.Room, error) {
 	return &m, nil
 }
 
-func (r *RoomRepository) Update(room *models.Room) error { return db.GetDB().Save(room).Error }
+// Update guarda el aula y registra room.updated en el outbox
+func (r *RoomRepository) Update(room *models.Room) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(room).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "room", room.ID, models.EventRoomUpdated, room)
+	})
+}
 
 func (r *RoomRepository) Delete(id uint) error { return db.GetDB().Delete(&models.Room{}, id).Error }
+
+func (r *RoomRepository) GetByCheckinToken(token string) (*models.Room, error) {
+	var m models.Room
+	if err := db.GetDB().Where("checkin_token = ?", token).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
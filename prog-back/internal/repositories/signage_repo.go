@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+)
+
+type SignageRepository struct{}
+
+func NewSignageRepository() *SignageRepository { return &SignageRepository{} }
+
+func (r *SignageRepository) Create(t *models.SignageToken) error {
+	return db.GetDB().Create(t).Error
+}
+
+func (r *SignageRepository) List() ([]models.SignageToken, error) {
+	var list []models.SignageToken
+	err := db.GetDB().Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *SignageRepository) GetByID(id uint) (*models.SignageToken, error) {
+	var t models.SignageToken
+	if err := db.GetDB().First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindActiveByHash busca un token no revocado por el hash de su valor
+func (r *SignageRepository) FindActiveByHash(hash string) (*models.SignageToken, error) {
+	var t models.SignageToken
+	if err := db.GetDB().Where("token_hash = ? AND revoked_at IS NULL", hash).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *SignageRepository) Revoke(id uint, at time.Time) error {
+	return db.GetDB().Model(&models.SignageToken{}).Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *SignageRepository) TouchLastUsed(id uint, at time.Time) error {
+	return db.GetDB().Model(&models.SignageToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
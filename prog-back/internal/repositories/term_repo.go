@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type TermRepository struct{}
+
+func NewTermRepository() *TermRepository { return &TermRepository{} }
+
+func (r *TermRepository) Create(t *models.Term) error {
+	return db.GetDB().Create(t).Error
+}
+
+func (r *TermRepository) GetAll() ([]models.Term, error) {
+	var list []models.Term
+	if err := db.GetDB().Order("start_date DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// FindCovering devuelve el período vigente que incluye la fecha, con sus feriados
+func (r *TermRepository) FindCovering(at time.Time) (*models.Term, error) {
+	var t models.Term
+	day := at.Format("2006-01-02")
+	err := db.GetDB().Preload("Holidays").
+		Where("start_date <= ? AND end_date >= ? AND archived_at IS NULL", day, day).
+		Order("start_date DESC").First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TermRepository) GetByID(id uint) (*models.Term, error) {
+	var t models.Term
+	if err := db.GetDB().Preload("Holidays").First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TermRepository) Update(t *models.Term) error {
+	return db.GetDB().Omit("Holidays").Save(t).Error
+}
+
+func (r *TermRepository) AddHoliday(h *models.TermHoliday) error {
+	return db.GetDB().Create(h).Error
+}
+
+func (r *TermRepository) DeleteHoliday(termID, holidayID uint) error {
+	return db.GetDB().Where("term_id = ?", termID).Delete(&models.TermHoliday{}, holidayID).Error
+}
+
+// FindEndedUnarchived devuelve los períodos ya finalizados que aún no se archivaron
+func (r *TermRepository) FindEndedUnarchived(today time.Time) ([]models.Term, error) {
+	var list []models.Term
+	err := db.GetDB().Where("end_date < ? AND archived_at IS NULL", today).Find(&list).Error
+	return list, err
+}
+
+// Archive marca el período y todas sus clases como archivados (sólo lectura)
+func (r *TermRepository) Archive(id uint, at time.Time) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Class{}).
+			Where("term_id = ? AND archived_at IS NULL", id).
+			Update("archived_at", at).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Term{}).Where("id = ?", id).Update("archived_at", at).Error
+	})
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type TimetableRepository struct{}
+
+func NewTimetableRepository() *TimetableRepository { return &TimetableRepository{} }
+
+func (r *TimetableRepository) Create(p *models.TimetableProposal) error {
+	return db.GetDB().Create(p).Error
+}
+
+func (r *TimetableRepository) GetByID(id uint) (*models.TimetableProposal, error) {
+	var p models.TimetableProposal
+	err := db.GetDB().Preload("Assignments", func(q *gorm.DB) *gorm.DB {
+		return q.Order("weekday ASC, start_time ASC")
+	}).First(&p, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *TimetableRepository) List(termID uint) ([]models.TimetableProposal, error) {
+	var list []models.TimetableProposal
+	q := db.GetDB().Order("created_at DESC")
+	if termID != 0 {
+		q = q.Where("term_id = ?", termID)
+	}
+	err := q.Find(&list).Error
+	return list, err
+}
+
+func (r *TimetableRepository) SetStatus(id uint, status string, committedAt *time.Time) error {
+	return db.GetDB().Model(&models.TimetableProposal{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "committed_at": committedAt}).Error
+}
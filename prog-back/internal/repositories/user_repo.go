@@ -1,8 +1,13 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"programcion-backend/internal/models"
 	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
 )
 
 type UserRepository struct{}
@@ -10,7 +15,85 @@ type UserRepository struct{}
 func NewUserRepository() *UserRepository { return &UserRepository{} }
 
 func (r *UserRepository) Create(user *models.User) error {
-	return db.GetDB().Create(user).Error
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "user", user.ID, models.EventUserCreated, user)
+	})
+}
+
+// CreateInvited crea la cuenta junto con su invitación
+func (r *UserRepository) CreateInvited(user *models.User, invite *models.UserInvite) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		invite.UserID = user.ID
+		if err := tx.Create(invite).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "user", user.ID, models.EventUserCreated, user)
+	})
+}
+
+func (r *UserRepository) FindInviteByHash(hash string) (*models.UserInvite, error) {
+	var inv models.UserInvite
+	if err := db.GetDB().Where("token_hash = ? AND accepted_at IS NULL", hash).First(&inv).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// FindInviteByUser devuelve la invitación de la cuenta o nil si no tiene
+func (r *UserRepository) FindInviteByUser(userID uint) (*models.UserInvite, error) {
+	var inv models.UserInvite
+	err := db.GetDB().Where("user_id = ?", userID).First(&inv).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// AcceptInvite fija la contraseña elegida y consume la invitación. Falla si
+// otra petición la consumió antes. Si el invitado aún espera su inscripción
+// la invitación queda marcada como aceptada en vez de borrarse.
+func (r *UserRepository) AcceptInvite(invite *models.UserInvite, passwordHash string) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		q := tx.Model(&models.UserInvite{}).Where("id = ? AND accepted_at IS NULL", invite.ID)
+		var res *gorm.DB
+		if invite.ClassID == nil {
+			res = q.Delete(&models.UserInvite{})
+		} else {
+			res = q.Update("accepted_at", time.Now())
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var u models.User
+		if err := tx.First(&u, invite.UserID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&u).Update("password_hash", passwordHash).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "user", u.ID, models.EventUserUpdated, &u)
+	})
+}
+
+// ClearInviteClass da por resuelta la inscripción pendiente de la invitación.
+// Si el invitado ya eligió su contraseña la invitación no sirve más y se borra.
+func (r *UserRepository) ClearInviteClass(invite *models.UserInvite) error {
+	if invite.AcceptedAt != nil {
+		return db.GetDB().Delete(&models.UserInvite{}, invite.ID).Error
+	}
+	return db.GetDB().Model(&models.UserInvite{}).Where("id = ?", invite.ID).Update("class_id", nil).Error
 }
 
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
@@ -30,7 +113,12 @@ func (r *UserRepository) GetByID(id uint) (*models.User, error) {
 }
 
 func (r *UserRepository) Update(user *models.User) error {
-	return db.GetDB().Save(user).Error
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "user", user.ID, models.EventUserUpdated, user)
+	})
 }
 
 func (r *UserRepository) FindWithFilters(role string, isConfirmed *bool) ([]models.User, error) {
@@ -48,3 +136,176 @@ func (r *UserRepository) FindWithFilters(role string, isConfirmed *bool) ([]mode
 	err := query.Find(&users).Error
 	return users, err
 }
+
+// Delete borra el usuario de forma lógica: cancela sus reservas futuras,
+// archiva sus inscripciones (promoviendo la lista de espera de esas clases) y
+// lo quita de los equipos docentes. capacities es el cupo efectivo de cada
+// clase en la que estaba inscrito (0 o ausente = sin límite).
+func (r *UserRepository) Delete(id uint, capacities map[uint]int) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if _, err := setReservationStatus(tx, "CANCELLED", models.EventReservationCancelled,
+			"user_id = ? AND status IN ? AND start_time >= ?", id, []string{"ACTIVE", "HELD"}, time.Now()); err != nil {
+			return err
+		}
+		var links []models.ClassStudent
+		if err := tx.Where("student_id = ? AND status IN ?", id, []string{"ENROLLED", "PENDING", "WAITLISTED"}).
+			Order("class_id ASC").Find(&links).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ClassStudent{}).
+			Where("student_id = ? AND status = ?", id, "ENROLLED").
+			Update("status", "ARCHIVED").Error; err != nil {
+			return err
+		}
+		if err := tx.Where("student_id = ? AND status IN ?", id, []string{"PENDING", "WAITLISTED"}).
+			Delete(&models.ClassStudent{}).Error; err != nil {
+			return err
+		}
+		for _, cs := range links {
+			status := ""
+			if cs.Status == "ENROLLED" {
+				status = "ARCHIVED"
+			}
+			if err := appendEnrollmentChange(tx, cs.ClassID, id, cs.Status, status); err != nil {
+				return err
+			}
+			if cs.Status == "ENROLLED" {
+				if _, err := promoteWaitlisted(tx, cs.ClassID, capacities[cs.ClassID]); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.ClassStaff{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.User{}, id).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "user", id, models.EventUserDeleted, map[string]uint{"id": id})
+	})
+}
+
+// FindDeleted devuelve los usuarios borrados lógicamente
+func (r *UserRepository) FindDeleted() ([]models.User, error) {
+	var users []models.User
+	err := db.GetDB().Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&users).Error
+	return users, err
+}
+
+func (r *UserRepository) FindDeletedByID(id uint) (*models.User, error) {
+	var u models.User
+	if err := db.GetDB().Unscoped().Where("deleted_at IS NOT NULL").First(&u, id).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Restore recupera el usuario y, en la misma transacción, lo vuelve a
+// inscribir en sus clases vigentes según el cupo de cada una (capacities, 0 o
+// ausente = sin límite). Devuelve el estado final por clase.
+func (r *UserRepository) Restore(id uint, capacities map[uint]int) (map[uint]string, error) {
+	statuses := map[uint]string{}
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.User{}).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		var classIDs []uint
+		if err := archivedEnrollments(tx, id).Order("class_id ASC").Pluck("class_id", &classIDs).Error; err != nil {
+			return err
+		}
+		for _, classID := range classIDs {
+			status, err := seatStatus(tx, classID, capacities[classID])
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.ClassStudent{}).
+				Where("class_id = ? AND student_id = ?", classID, id).
+				Updates(map[string]interface{}{"status": status, "enrolled_at": time.Now()}).Error; err != nil {
+				return err
+			}
+			if err := appendEnrollmentChange(tx, classID, id, "ARCHIVED", status); err != nil {
+				return err
+			}
+			statuses[classID] = status
+		}
+		var u models.User
+		if err := tx.First(&u, id).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, "user", id, models.EventUserRestored, &u)
+	})
+	return statuses, err
+}
+
+// ArchivedEnrollments devuelve las clases vigentes en las que el usuario tenía
+// una inscripción archivada
+func (r *UserRepository) ArchivedEnrollments(id uint) ([]uint, error) {
+	var ids []uint
+	err := archivedEnrollments(db.GetDB(), id).Pluck("class_id", &ids).Error
+	return ids, err
+}
+
+func archivedEnrollments(tx *gorm.DB, id uint) *gorm.DB {
+	return tx.Model(&models.ClassStudent{}).
+		Where("student_id = ? AND status = ?", id, "ARCHIVED").
+		Where("class_id IN (SELECT id FROM classes WHERE deleted_at IS NULL AND archived_at IS NULL)")
+}
+
+// CountReservations cuenta todas las reservas del usuario, en cualquier estado
+func (r *UserRepository) CountReservations(id uint) (int64, error) {
+	var count int64
+	err := db.GetDB().Model(&models.Reservation{}).Where("user_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountActiveSignageTokens cuenta los tokens de cartelería sin revocar que
+// creó el usuario: sus reservas instantáneas quedan a su nombre
+func (r *UserRepository) CountActiveSignageTokens(id uint) (int64, error) {
+	var count int64
+	err := db.GetDB().Model(&models.SignageToken{}).
+		Where("created_by_id = ? AND revoked_at IS NULL", id).Count(&count).Error
+	return count, err
+}
+
+// Purge elimina definitivamente un usuario borrado, sus datos de clase y sus
+// notificaciones. Los exámenes, propuestas, webhooks, tokens revocados y
+// asistencias que registró se conservan sin autor.
+// Quien llama debe verificar antes que no tenga reservas, clases propias ni
+// tokens de cartelería vigentes.
+func (r *UserRepository) Purge(id uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		steps := []string{
+			"DELETE FROM class_students WHERE student_id = ?",
+			"DELETE FROM class_staffs WHERE user_id = ?",
+			"DELETE FROM announcement_reads WHERE user_id = ?",
+			"UPDATE announcements SET author_id = NULL WHERE author_id = ?",
+			"UPDATE exams SET created_by_id = NULL WHERE created_by_id = ?",
+			"UPDATE timetable_proposals SET created_by_id = NULL WHERE created_by_id = ?",
+			"UPDATE webhook_subscriptions SET created_by_id = NULL WHERE created_by_id = ?",
+			"UPDATE signage_tokens SET created_by_id = NULL WHERE created_by_id = ? AND revoked_at IS NOT NULL",
+			"UPDATE attendances SET marked_by_id = NULL WHERE marked_by_id = ?",
+			"DELETE FROM attendances WHERE student_id = ?",
+			"DELETE FROM exam_seats WHERE student_id = ?",
+			"DELETE FROM exam_invigilators WHERE user_id = ?",
+			"DELETE FROM user_invites WHERE user_id = ?",
+			"DELETE FROM notifications WHERE user_id = ?",
+			"DELETE FROM notification_deliveries WHERE user_id = ?",
+			"DELETE FROM notification_preferences WHERE user_id = ?",
+		}
+		for _, q := range steps {
+			if err := tx.Exec(q, id).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&models.User{}, id).Error
+	})
+}
+
+func (r *UserRepository) FindByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := db.GetDB().Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct{}
+
+func NewWebhookRepository() *WebhookRepository { return &WebhookRepository{} }
+
+func (r *WebhookRepository) Create(s *models.WebhookSubscription) error {
+	return db.GetDB().Create(s).Error
+}
+
+func (r *WebhookRepository) GetByID(id uint) (*models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	if err := db.GetDB().First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FindByID es como GetByID pero devuelve nil sin error si la suscripción no existe
+func (r *WebhookRepository) FindByID(id uint) (*models.WebhookSubscription, error) {
+	s, err := r.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (r *WebhookRepository) List() ([]models.WebhookSubscription, error) {
+	var list []models.WebhookSubscription
+	err := db.GetDB().Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) FindActive() ([]models.WebhookSubscription, error) {
+	var list []models.WebhookSubscription
+	err := db.GetDB().Where("active = ?", true).Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) Update(s *models.WebhookSubscription) error {
+	return db.GetDB().Save(s).Error
+}
+
+// Delete borra la suscripción y su historial de entregas
+func (r *WebhookRepository) Delete(id uint) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookSubscription{}, id).Error
+	})
+}
+
+func (r *WebhookRepository) CreateDeliveries(list []models.WebhookDelivery) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return db.GetDB().Create(&list).Error
+}
+
+// SubscriptionsWithEvent devuelve las suscripciones que ya tienen entregas del evento
+func (r *WebhookRepository) SubscriptionsWithEvent(eventID string) ([]uint, error) {
+	var ids []uint
+	err := db.GetDB().Model(&models.WebhookDelivery{}).Where("event_id = ?", eventID).
+		Distinct().Pluck("subscription_id", &ids).Error
+	return ids, err
+}
+
+func (r *WebhookRepository) GetDelivery(id uint) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	if err := db.GetDB().First(&d, id).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(subscriptionID uint, status string, limit int) ([]models.WebhookDelivery, error) {
+	var list []models.WebhookDelivery
+	q := db.GetDB().Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("id DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// ClaimDueDeliveries toma entregas pendientes vencidas y las reserva durante
+// lease (ver NotificationRepository). Incluye las de suscripciones inactivas
+// para que el dispatcher las descarte en vez de dejarlas pendientes
+func (r *WebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var list []models.WebhookDelivery
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", "PENDING", now).
+			Order("id ASC").Limit(limit).Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(list))
+		for i, d := range list {
+			ids[i] = d.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return list, err
+}
+
+// MarkDead da por fallida una entrega que ya no puede enviarse (suscripción
+// borrada o deshabilitada), sin contarla como fallo del endpoint
+func (r *WebhookRepository) MarkDead(d *models.WebhookDelivery, reason string) error {
+	return db.GetDB().Model(&models.WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+		"status":     "FAILED",
+		"last_error": reason,
+	}).Error
+}
+
+// MarkSucceeded registra la entrega y reinicia el contador de fallos de la suscripción
+func (r *WebhookRepository) MarkSucceeded(d *models.WebhookDelivery, statusCode int, at time.Time) error {
+	return db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+			"status":           "SUCCEEDED",
+			"attempts":         d.Attempts + 1,
+			"last_status_code": statusCode,
+			"last_error":       "",
+			"delivered_at":     at,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookSubscription{}).Where("id = ?", d.SubscriptionID).
+			Update("consecutive_failures", 0).Error
+	})
+}
+
+// MarkFailed registra un intento fallido (next == nil: sin más reintentos) y
+// deshabilita la suscripción al llegar a disableAfter fallos consecutivos.
+// Devuelve true si la suscripción quedó deshabilitada.
+func (r *WebhookRepository) MarkFailed(d *models.WebhookDelivery, statusCode int, lastErr string, next *time.Time, disableAfter int) (bool, error) {
+	disabled := false
+	err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"attempts":         d.Attempts + 1,
+			"last_status_code": statusCode,
+			"last_error":       lastErr,
+		}
+		if next != nil {
+			updates["next_attempt_at"] = *next
+		} else {
+			updates["status"] = "FAILED"
+		}
+		if err := tx.Model(&models.WebhookDelivery{}).Where("id = ?", d.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		var sub models.WebhookSubscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sub, d.SubscriptionID).Error; err != nil {
+			return err
+		}
+		sub.ConsecutiveFailures++
+		if sub.Active && sub.ConsecutiveFailures >= disableAfter {
+			now := time.Now()
+			sub.Active = false
+			sub.DisabledAt = &now
+			disabled = true
+		}
+		return tx.Model(&sub).Select("consecutive_failures", "active", "disabled_at").Updates(&sub).Error
+	})
+	return disabled, err
+}
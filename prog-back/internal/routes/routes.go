@@ -3,69 +3,186 @@ package routes
 import (
 	"programcion-backend/internal/controllers"
 	"programcion-backend/internal/middleware"
+	"programcion-backend/pkg/config"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine) {
+func SetupRoutes(r *gin.Engine, cfg *config.Config) {
+	controllers.Init(cfg)
+	requireAuth := middleware.RequireAuthentication(cfg.Auth.JWTSecret)
+
 	api := r.Group("/api")
 	{
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", controllers.Register)
 			auth.POST("/login", controllers.Login)
-			auth.GET("/me", middleware.RequireAuthentication(), controllers.AuthMe)
+			auth.POST("/accept-invite", controllers.AcceptInvite)
+			auth.GET("/me", requireAuth, controllers.AuthMe)
 			auth.POST("/logout", controllers.Logout)
 		}
 
 		users := api.Group("/users")
 		{
-			users.GET("", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.ListUsers)
-			users.GET("/:id", middleware.RequireAuthentication(), controllers.GetUser)
-			users.PATCH("/:id/confirm", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.ConfirmUser)
+			users.GET("", requireAuth, middleware.RequireRole("ADMIN"), controllers.ListUsers)
+			users.GET("/:id", requireAuth, controllers.GetUser)
+			users.PATCH("/:id/confirm", requireAuth, middleware.RequireRole("ADMIN"), controllers.ConfirmUser)
+			users.DELETE("/:id", requireAuth, middleware.RequireRole("ADMIN"), controllers.DeleteUser)
+			users.GET("/deleted", requireAuth, middleware.RequireRole("ADMIN"), controllers.ListDeletedUsers)
+			users.POST("/:id/restore", requireAuth, middleware.RequireRole("ADMIN"), controllers.RestoreUser)
+			users.DELETE("/:id/purge", requireAuth, middleware.RequireRole("ADMIN"), controllers.PurgeUser)
 		}
 
 		buildings := api.Group("/buildings")
 		{
 			buildings.GET("", controllers.ListBuildings)
-			buildings.POST("", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.CreateBuilding)
+			buildings.POST("", requireAuth, middleware.RequireRole("ADMIN"), controllers.CreateBuilding)
 			buildings.GET("/:id", controllers.GetBuilding)
 		}
 
+		terms := api.Group("/terms")
+		{
+			terms.GET("", requireAuth, controllers.ListTerms)
+			terms.POST("", requireAuth, middleware.RequireRole("ADMIN"), controllers.CreateTerm)
+			terms.GET("/:id", requireAuth, controllers.GetTerm)
+			terms.PATCH("/:id", requireAuth, middleware.RequireRole("ADMIN"), controllers.UpdateTerm)
+			terms.POST("/:id/holidays", requireAuth, middleware.RequireRole("ADMIN"), controllers.AddTermHoliday)
+			terms.DELETE("/:id/holidays/:holiday_id", requireAuth, middleware.RequireRole("ADMIN"), controllers.DeleteTermHoliday)
+		}
+
 		rooms := api.Group("/rooms")
 		{
 			rooms.GET("", controllers.ListRooms)
-			rooms.POST("", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.CreateRoom)
+			rooms.POST("", requireAuth, middleware.RequireRole("ADMIN"), controllers.CreateRoom)
 			rooms.GET("/:id", controllers.GetRoom)
-			rooms.PATCH("/:id", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.UpdateRoom)
-			rooms.DELETE("/:id", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.DeleteRoom)
+			rooms.PATCH("/:id", requireAuth, middleware.RequireRole("ADMIN"), controllers.UpdateRoom)
+			rooms.DELETE("/:id", requireAuth, middleware.RequireRole("ADMIN"), controllers.DeleteRoom)
+			rooms.POST("/:id/checkin-token", requireAuth, middleware.RequireRole("ADMIN"), controllers.RegenerateRoomCheckinToken)
+			rooms.POST("/checkin", requireAuth, controllers.CheckInByQR)
 		}
 
 		reservations := api.Group("/reservations")
 		{
-			reservations.POST("", middleware.RequireAuthentication(), middleware.RequireRole("PROFESSOR"), controllers.CreateReservation)
-			reservations.GET("", middleware.RequireAuthentication(), controllers.ListReservations)
-			reservations.GET("/:id", middleware.RequireAuthentication(), controllers.GetReservation)
-			reservations.PATCH("/:id", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.CancelReservation)
-			reservations.DELETE("/:id", middleware.RequireAuthentication(), middleware.RequireRole("ADMIN"), controllers.CancelReservation)
+			reservations.POST("", requireAuth, middleware.RequireRole("PROFESSOR"), controllers.CreateReservation)
+			reservations.GET("", requireAuth, controllers.ListReservations)
+			reservations.GET("/stream", requireAuth, controllers.StreamEvents)
+			reservations.GET("/:id", requireAuth, controllers.GetReservation)
+			reservations.PATCH("/:id", requireAuth, middleware.RequireRole("ADMIN"), controllers.CancelReservation)
+			reservations.DELETE("/:id", requireAuth, middleware.RequireRole("ADMIN"), controllers.CancelReservation)
+			reservations.POST("/:id/checkin", requireAuth, controllers.CheckInReservation)
+			reservations.GET("/:id/attendance-code", requireAuth, controllers.GetAttendanceCode)
+			reservations.GET("/:id/attendance", requireAuth, controllers.ListSessionAttendance)
+			reservations.POST("/:id/attendance/self", requireAuth, middleware.RequireRole("STUDENT"), controllers.MarkOwnAttendance)
+			reservations.PUT("/:id/attendance/:student_id", requireAuth, controllers.SetStudentAttendance)
+			reservations.POST("/holds", requireAuth, middleware.RequireRole("PROFESSOR"), controllers.CreateHold)
+			reservations.POST("/holds/:id/confirm", requireAuth, middleware.RequireRole("PROFESSOR"), controllers.ConfirmHold)
+			reservations.DELETE("/holds/:id", requireAuth, middleware.RequireRole("PROFESSOR"), controllers.ReleaseHold)
+		}
+
+		timetable := api.Group("/timetable", requireAuth, middleware.RequireRole("ADMIN"))
+		{
+			timetable.POST("/proposals", controllers.CreateTimetableProposal)
+			timetable.GET("/proposals", controllers.ListTimetableProposals)
+			timetable.GET("/proposals/:id", controllers.GetTimetableProposal)
+			timetable.POST("/proposals/:id/commit", controllers.CommitTimetableProposal)
+			timetable.DELETE("/proposals/:id", controllers.DiscardTimetableProposal)
+		}
+
+		webhooks := api.Group("/webhooks", requireAuth, middleware.RequireRole("ADMIN"))
+		{
+			webhooks.POST("", controllers.CreateWebhook)
+			webhooks.GET("", controllers.ListWebhooks)
+			webhooks.GET("/:id", controllers.GetWebhook)
+			webhooks.PATCH("/:id", controllers.UpdateWebhook)
+			webhooks.DELETE("/:id", controllers.DeleteWebhook)
+			webhooks.POST("/:id/rotate-secret", controllers.RotateWebhookSecret)
+			webhooks.GET("/:id/deliveries", controllers.ListWebhookDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", controllers.RedeliverWebhook)
+		}
+
+		reports := api.Group("/reports")
+		{
+			reports.GET("/no-shows", requireAuth, middleware.RequireRole("ADMIN"), controllers.GetNoShowReport)
 		}
 
 		classes := api.Group("/classes")
 		{
-			classes.POST("", middleware.RequireAuthentication(), middleware.RequireRole("PROFESSOR"), controllers.CreateClass)
-			classes.GET("", middleware.RequireAuthentication(), controllers.ListClasses)
-			classes.GET("/:id", middleware.RequireAuthentication(), controllers.GetClass)
-			classes.PATCH("/:id", middleware.RequireAuthentication(), controllers.UpdateClass)
-			classes.DELETE("/:id", middleware.RequireAuthentication(), controllers.DeleteClass)
-			classes.POST("/:id/students", middleware.RequireAuthentication(), middleware.RequireRole("PROFESSOR"), controllers.AddStudent)
-			classes.DELETE("/:id/students/:student_id", middleware.RequireAuthentication(), middleware.RequireRole("PROFESSOR"), controllers.RemoveStudent)
-			classes.GET("/:id/students", middleware.RequireAuthentication(), controllers.ListClassStudents)
+			classes.POST("", requireAuth, middleware.RequireRole("PROFESSOR"), controllers.CreateClass)
+			classes.GET("", requireAuth, controllers.ListClasses)
+			classes.GET("/:id", requireAuth, controllers.GetClass)
+			classes.PATCH("/:id", requireAuth, controllers.UpdateClass)
+			classes.DELETE("/:id", requireAuth, controllers.DeleteClass)
+			classes.GET("/deleted", requireAuth, middleware.RequireRole("ADMIN"), controllers.ListDeletedClasses)
+			classes.POST("/:id/restore", requireAuth, middleware.RequireRole("ADMIN"), controllers.RestoreClass)
+			classes.DELETE("/:id/purge", requireAuth, middleware.RequireRole("ADMIN"), controllers.PurgeClass)
+			classes.POST("/:id/students", requireAuth, controllers.AddStudent)
+			classes.DELETE("/:id/students/:student_id", requireAuth, controllers.RemoveStudent)
+			classes.GET("/:id/students", requireAuth, controllers.ListClassStudents)
+			classes.POST("/:id/students/import", requireAuth, controllers.ImportClassRoster)
+			classes.GET("/:id/students/export", requireAuth, controllers.ExportClassRoster)
+			classes.GET("/:id/staff", requireAuth, controllers.ListClassStaff)
+			classes.POST("/:id/staff", requireAuth, controllers.AddClassStaff)
+			classes.DELETE("/:id/staff/:user_id", requireAuth, controllers.RemoveClassStaff)
+			classes.GET("/:id/waitlist", requireAuth, controllers.ListClassWaitlist)
+			classes.POST("/join", requireAuth, middleware.RequireRole("STUDENT"), controllers.JoinClass)
+			classes.GET("/:id/join-code", requireAuth, controllers.GetJoinCode)
+			classes.POST("/:id/join-code", requireAuth, controllers.RegenerateJoinCode)
+			classes.DELETE("/:id/join-code", requireAuth, controllers.DisableJoinCode)
+			classes.GET("/:id/requests", requireAuth, controllers.ListEnrollmentRequests)
+			classes.POST("/:id/requests/:student_id/approve", requireAuth, controllers.ApproveEnrollmentRequest)
+			classes.DELETE("/:id/requests/:student_id", requireAuth, controllers.RejectEnrollmentRequest)
+			classes.GET("/:id/attendance", requireAuth, controllers.GetClassAttendanceReport)
+			classes.GET("/:id/sessions", requireAuth, controllers.ListClassSessions)
+			classes.POST("/:id/sessions", requireAuth, controllers.CreateClassSession)
+			classes.PATCH("/:id/sessions/:session_id", requireAuth, controllers.UpdateClassSession)
+			classes.DELETE("/:id/sessions/:session_id", requireAuth, controllers.DeleteClassSession)
+			classes.GET("/:id/announcements", requireAuth, controllers.ListClassAnnouncements)
+			classes.POST("/:id/announcements", requireAuth, controllers.CreateClassAnnouncement)
+			classes.DELETE("/:id/announcements/:announcement_id", requireAuth, controllers.DeleteClassAnnouncement)
+			classes.GET("/:id/exams", requireAuth, controllers.ListClassExams)
+			classes.POST("/:id/exams", requireAuth, controllers.CreateExam)
+		}
+
+		exams := api.Group("/exams")
+		{
+			exams.GET("/:id", requireAuth, controllers.GetExam)
+			exams.DELETE("/:id", requireAuth, controllers.CancelExam)
+			exams.GET("/:id/rooms/:room_id/seating", requireAuth, controllers.GetExamSeating)
+		}
+
+		announcements := api.Group("/announcements")
+		{
+			announcements.POST("/:id/read", requireAuth, controllers.MarkAnnouncementRead)
+		}
+
+		// Endpoints del usuario autenticado
+		me := api.Group("/me")
+		{
+			me.GET("/announcements", requireAuth, controllers.ListMyAnnouncements)
+			me.GET("/timetable", requireAuth, middleware.RequireRole("STUDENT"), controllers.GetMyTimetable)
+			me.GET("/notifications", requireAuth, controllers.ListMyNotifications)
+			me.POST("/notifications/:id/read", requireAuth, controllers.MarkNotificationRead)
+			me.POST("/notifications/read-all", requireAuth, controllers.MarkAllNotificationsRead)
+			me.GET("/notification-settings", requireAuth, controllers.GetNotificationSettings)
+			me.PUT("/notification-settings", requireAuth, controllers.UpdateNotificationSettings)
 		}
 
 		// Public endpoints
 		public := api.Group("/public")
 		{
 			public.GET("/reservations", controllers.GetPublicReservations)
+			// Pantallas de aulas y edificios, autenticadas con su token (X-Signage-Token)
+			public.GET("/signage/rooms/:id", controllers.GetRoomSignage)
+			public.POST("/signage/rooms/:id/instant-booking", controllers.InstantBookRoom)
+			public.GET("/signage/buildings/:id", controllers.GetBuildingSignage)
+		}
+
+		signage := api.Group("/signage-tokens", requireAuth, middleware.RequireRole("ADMIN"))
+		{
+			signage.POST("", controllers.CreateSignageToken)
+			signage.GET("", controllers.ListSignageTokens)
+			signage.DELETE("/:id", controllers.RevokeSignageToken)
 		}
 	}
 }
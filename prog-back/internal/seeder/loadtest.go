@@ -0,0 +1,343 @@
+package seeder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"programcion-backend/internal/models"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Los datos de carga llevan nombres deterministas ("LT ...") para que cada
+// ejecución sólo cree lo que falte y para poder reconocerlos y borrarlos.
+const (
+	loadTestPrefix      = "LT "
+	loadTestEmailDomain = "loadtest.invalid"
+	loadTestPassword    = "loadtest123"
+	loadTestBatchSize   = 500
+	loadTestSlotsPerDay = 10 // franjas de 1 h entre las 8:00 y las 18:00
+)
+
+type LoadTestOptions struct {
+	Buildings        int
+	RoomsPerBuilding int
+	Professors       int
+	Students         int
+	Classes          int
+	StudentsPerClass int
+	Reservations     int
+}
+
+func DefaultLoadTestOptions() LoadTestOptions {
+	return LoadTestOptions{
+		Buildings:        1000,
+		RoomsPerBuilding: 5,
+		Professors:       200,
+		Students:         2000,
+		Classes:          2000,
+		StudentsPerClass: 20,
+		Reservations:     20000,
+	}
+}
+
+func (o LoadTestOptions) validate() error {
+	if o.Buildings < 0 || o.RoomsPerBuilding < 0 || o.Professors < 0 || o.Students < 0 ||
+		o.Classes < 0 || o.StudentsPerClass < 0 || o.Reservations < 0 {
+		return errors.New("load-test sizes cannot be negative")
+	}
+	if (o.Classes > 0 || o.Reservations > 0) && o.Professors == 0 {
+		return errors.New("load-test classes and reservations need at least one professor")
+	}
+	if o.Reservations > 0 && o.Buildings*o.RoomsPerBuilding == 0 {
+		return errors.New("load-test reservations need at least one room")
+	}
+	if o.StudentsPerClass > o.Students {
+		return errors.New("load-test students per class cannot exceed students")
+	}
+	return nil
+}
+
+// seedLoadTest genera edificios, aulas, usuarios, clases con inscripciones y
+// reservas sin solaparse. Inserta directamente en lotes, sin pasar por los
+// servicios, así que no genera eventos de dominio ni notificaciones.
+func seedLoadTest(dbConn *gorm.DB, opts LoadTestOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	start := time.Now()
+
+	buildingIDs, err := seedLoadTestBuildings(dbConn, opts)
+	if err != nil {
+		return err
+	}
+	roomIDs, err := seedLoadTestRooms(dbConn, opts, buildingIDs)
+	if err != nil {
+		return err
+	}
+	profIDs, studentIDs, err := seedLoadTestUsers(dbConn, opts)
+	if err != nil {
+		return err
+	}
+	classIDs, err := seedLoadTestClasses(dbConn, opts, profIDs, studentIDs)
+	if err != nil {
+		return err
+	}
+	if err := seedLoadTestReservations(dbConn, opts, roomIDs, profIDs, classIDs); err != nil {
+		return err
+	}
+	log.Infof("Seed load-test completado en %s", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func seedLoadTestBuildings(dbConn *gorm.DB, opts LoadTestOptions) ([]uint, error) {
+	var existing []models.Building
+	if err := dbConn.Where("name LIKE ?", loadTestPrefix+"Edificio %").Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byName := map[string]uint{}
+	for _, b := range existing {
+		byName[b.Name] = b.ID
+	}
+	var toCreate []models.Building
+	for i := 1; i <= opts.Buildings; i++ {
+		name := fmt.Sprintf("%sEdificio %04d", loadTestPrefix, i)
+		if _, ok := byName[name]; !ok {
+			toCreate = append(toCreate, models.Building{Name: name, Address: fmt.Sprintf("Calle Carga %d", i), Campus: fmt.Sprintf("Campus %d", i%10)})
+		}
+	}
+	if err := createInBatches(dbConn, &toCreate); err != nil {
+		return nil, err
+	}
+	for _, b := range toCreate {
+		byName[b.Name] = b.ID
+	}
+	logCreated("edificios", len(toCreate), opts.Buildings)
+
+	ids := make([]uint, 0, opts.Buildings)
+	for i := 1; i <= opts.Buildings; i++ {
+		ids = append(ids, byName[fmt.Sprintf("%sEdificio %04d", loadTestPrefix, i)])
+	}
+	return ids, nil
+}
+
+func seedLoadTestRooms(dbConn *gorm.DB, opts LoadTestOptions, buildingIDs []uint) ([]uint, error) {
+	type roomKey struct {
+		buildingID uint
+		name       string
+	}
+	var existing []models.Room
+	if err := dbConn.Where("name LIKE ?", loadTestPrefix+"Aula %").Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byKey := map[roomKey]uint{}
+	for _, r := range existing {
+		byKey[roomKey{r.BuildingID, r.Name}] = r.ID
+	}
+	roomName := func(b, r int) string { return fmt.Sprintf("%sAula %04d-%02d", loadTestPrefix, b+1, r+1) }
+
+	var toCreate []models.Room
+	for b, bid := range buildingIDs {
+		for r := 0; r < opts.RoomsPerBuilding; r++ {
+			if _, ok := byKey[roomKey{bid, roomName(b, r)}]; !ok {
+				capacity := 20 + 10*(r%5)
+				toCreate = append(toCreate, models.Room{BuildingID: bid, Name: roomName(b, r), Capacity: capacity, Resources: `["pizarra","proyector"]`})
+			}
+		}
+	}
+	if err := createInBatches(dbConn, &toCreate); err != nil {
+		return nil, err
+	}
+	for _, r := range toCreate {
+		byKey[roomKey{r.BuildingID, r.Name}] = r.ID
+	}
+	logCreated("aulas", len(toCreate), len(buildingIDs)*opts.RoomsPerBuilding)
+
+	ids := make([]uint, 0, len(buildingIDs)*opts.RoomsPerBuilding)
+	for b, bid := range buildingIDs {
+		for r := 0; r < opts.RoomsPerBuilding; r++ {
+			ids = append(ids, byKey[roomKey{bid, roomName(b, r)}])
+		}
+	}
+	return ids, nil
+}
+
+// seedLoadTestUsers crea los usuarios con un único hash de loadTestPassword;
+// el email único hace que los ya existentes se ignoren
+func seedLoadTestUsers(dbConn *gorm.DB, opts LoadTestOptions) ([]uint, []uint, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(loadTestPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, nil, err
+	}
+	build := func(kind, label, role string, n int) ([]uint, error) {
+		if n == 0 {
+			return nil, nil
+		}
+		emails := make([]string, 0, n)
+		users := make([]models.User, 0, n)
+		for i := 1; i <= n; i++ {
+			email := fmt.Sprintf("lt-%s-%05d@%s", kind, i, loadTestEmailDomain)
+			emails = append(emails, email)
+			users = append(users, models.User{
+				Name:         fmt.Sprintf("%s%s %05d", loadTestPrefix, label, i),
+				Email:        email,
+				PasswordHash: string(hash),
+				Role:         role,
+				IsConfirmed:  true,
+			})
+		}
+		res := dbConn.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&users, loadTestBatchSize)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		logCreated("usuarios "+role, int(res.RowsAffected), n)
+
+		var rows []models.User
+		for i := 0; i < len(emails); i += loadTestBatchSize {
+			end := min(i+loadTestBatchSize, len(emails))
+			var chunk []models.User
+			if err := dbConn.Unscoped().Select("id", "email").Where("email IN ?", emails[i:end]).Find(&chunk).Error; err != nil {
+				return nil, err
+			}
+			rows = append(rows, chunk...)
+		}
+		byEmail := map[string]uint{}
+		for _, u := range rows {
+			byEmail[u.Email] = u.ID
+		}
+		ids := make([]uint, 0, n)
+		for _, e := range emails {
+			ids = append(ids, byEmail[e])
+		}
+		return ids, nil
+	}
+	profIDs, err := build("prof", "Profesor", "PROFESSOR", opts.Professors)
+	if err != nil {
+		return nil, nil, err
+	}
+	studentIDs, err := build("student", "Alumno", "STUDENT", opts.Students)
+	if err != nil {
+		return nil, nil, err
+	}
+	return profIDs, studentIDs, nil
+}
+
+// seedLoadTestClasses reparte las clases entre los profesores e inscribe en
+// cada una StudentsPerClass estudiantes consecutivos (en rueda)
+func seedLoadTestClasses(dbConn *gorm.DB, opts LoadTestOptions, profIDs, studentIDs []uint) ([]uint, error) {
+	var existing []models.Class
+	if err := dbConn.Unscoped().Select("id", "name").Where("name LIKE ?", loadTestPrefix+"Clase %").Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byName := map[string]uint{}
+	for _, c := range existing {
+		byName[c.Name] = c.ID
+	}
+	className := func(i int) string { return fmt.Sprintf("%sClase %05d", loadTestPrefix, i+1) }
+
+	var toCreate []models.Class
+	for i := 0; i < opts.Classes; i++ {
+		if _, ok := byName[className(i)]; !ok {
+			toCreate = append(toCreate, models.Class{
+				Name:        className(i),
+				Subject:     fmt.Sprintf("Materia %d", i%50),
+				ProfessorID: profIDs[i%len(profIDs)],
+			})
+		}
+	}
+	// Omitir asociaciones: Students es many2many y no debe tocar class_students
+	if err := createInBatches(dbConn.Omit(clause.Associations), &toCreate); err != nil {
+		return nil, err
+	}
+	for _, c := range toCreate {
+		byName[c.Name] = c.ID
+	}
+	logCreated("clases", len(toCreate), opts.Classes)
+
+	ids := make([]uint, 0, opts.Classes)
+	for i := 0; i < opts.Classes; i++ {
+		ids = append(ids, byName[className(i)])
+	}
+
+	if len(ids) == 0 || opts.StudentsPerClass == 0 {
+		return ids, nil
+	}
+	enrollments := make([]models.ClassStudent, 0, len(ids)*opts.StudentsPerClass)
+	for i, cid := range ids {
+		for j := 0; j < opts.StudentsPerClass; j++ {
+			sid := studentIDs[(i*opts.StudentsPerClass+j)%len(studentIDs)]
+			enrollments = append(enrollments, models.ClassStudent{ClassID: cid, StudentID: sid, Status: "ENROLLED"})
+		}
+	}
+	res := dbConn.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&enrollments, loadTestBatchSize)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	logCreated("inscripciones", int(res.RowsAffected), len(enrollments))
+	return ids, nil
+}
+
+// seedLoadTestReservations reparte las reservas en franjas de 1 h a partir de
+// mañana: la reserva k ocupa el aula k % aulas en la franja k / aulas, así que
+// nunca se solapan. El motivo ("LT reserva N") identifica a cada una.
+func seedLoadTestReservations(dbConn *gorm.DB, opts LoadTestOptions, roomIDs, profIDs, classIDs []uint) error {
+	if opts.Reservations == 0 {
+		return nil
+	}
+	var existing []string
+	if err := dbConn.Model(&models.Reservation{}).Where("purpose LIKE ?", loadTestPrefix+"reserva %").Pluck("purpose", &existing).Error; err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		seen[p] = true
+	}
+
+	now := time.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	var toCreate []models.Reservation
+	for k := 0; k < opts.Reservations; k++ {
+		purpose := fmt.Sprintf("%sreserva %06d", loadTestPrefix, k+1)
+		if seen[purpose] {
+			continue
+		}
+		slot := k / len(roomIDs)
+		startTime := tomorrow.AddDate(0, 0, slot/loadTestSlotsPerDay).Add(time.Duration(8+slot%loadTestSlotsPerDay) * time.Hour)
+		r := models.Reservation{
+			RoomID:             roomIDs[k%len(roomIDs)],
+			UserID:             profIDs[k%len(profIDs)],
+			StartTime:          startTime.UTC(),
+			EndTime:            startTime.Add(time.Hour).UTC(),
+			Purpose:            purpose,
+			EstimatedAttendees: opts.StudentsPerClass,
+			Status:             "ACTIVE",
+		}
+		if len(classIDs) > 0 {
+			i := k % len(classIDs)
+			cid := classIDs[i]
+			r.ClassID = &cid
+			r.UserID = profIDs[i%len(profIDs)]
+		}
+		toCreate = append(toCreate, r)
+	}
+	if err := createInBatches(dbConn.Omit(clause.Associations), &toCreate); err != nil {
+		return err
+	}
+	logCreated("reservas", len(toCreate), opts.Reservations)
+	return nil
+}
+
+func createInBatches[T any](dbConn *gorm.DB, rows *[]T) error {
+	if len(*rows) == 0 {
+		return nil
+	}
+	return dbConn.CreateInBatches(rows, loadTestBatchSize).Error
+}
+
+func logCreated(what string, created, total int) {
+	log.Infof("Load test: %d %s creados (%d ya existían)", created, what, total-created)
+}
@@ -2,13 +2,14 @@ package seeder
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
-	"os"
 	"strings"
 	"time"
 
 	"programcion-backend/internal/models"
+	"programcion-backend/pkg/config"
 	"programcion-backend/pkg/db"
 
 	log "github.com/sirupsen/logrus"
@@ -16,53 +17,105 @@ import (
 	"gorm.io/gorm"
 )
 
+//go:embed sql/*.sql
 var seedFS embed.FS
 
-// SeedAdmin performs the following steps in order:
-// 1. Create admin user if ADMIN_* env vars are provided (keeps existing behavior)
-// 2. Create several sample users (professors and students) with hashed passwords
-// 3. Execute embedded SQL files (buildings, rooms)
-// 4. Create sample reservations programmatically linking real user and room IDs
-func SeedAdmin() error {
+// Perfiles del subcomando `seed`
+const (
+	ProfileAdminOnly = "admin-only" // sólo el administrador de ADMIN_*
+	ProfileDemo      = "demo"       // admin, usuarios de ejemplo, edificios, aulas y reservas
+	ProfileLoadTest  = "load-test"  // volumen sintético para pruebas de carga
+)
+
+var Profiles = []string{ProfileAdminOnly, ProfileDemo, ProfileLoadTest}
+
+// ErrDemoNotAllowed se devuelve al pedir datos ficticios sin habilitarlos:
+// los usuarios de ejemplo tienen contraseñas conocidas.
+var ErrDemoNotAllowed = errors.New("demo data is disabled; pass --allow-demo or set SEED_ALLOW_DEMO=true")
+
+type Options struct {
+	// AllowDemo habilita los perfiles con datos ficticios (demo y load-test)
+	AllowDemo bool
+	Admin     config.AdminConfig
+	LoadTest  LoadTestOptions
+}
+
+// Run ejecuta un perfil. Todos son idempotentes: volver a ejecutarlos sólo
+// crea lo que falte.
+func Run(profile string, opts Options) error {
 	dbConn := db.GetDB()
 	if dbConn == nil {
-		log.Error("DB no inicializada en seeder")
-		return nil
+		return errors.New("database not initialized")
 	}
+	switch profile {
+	case ProfileAdminOnly:
+		return seedAdmin(dbConn, opts.Admin, true)
+	case ProfileDemo:
+		if !opts.AllowDemo {
+			return ErrDemoNotAllowed
+		}
+		if err := seedAdmin(dbConn, opts.Admin, false); err != nil {
+			return err
+		}
+		if err := seedSampleUsers(dbConn); err != nil {
+			return err
+		}
+		// Edificios y aulas desde los SQL embebidos
+		if err := runSQLSeeds(dbConn); err != nil {
+			return err
+		}
+		// Reservas creadas en Go para enlazar IDs reales de usuarios y aulas
+		return createSampleReservations(dbConn)
+	case ProfileLoadTest:
+		if !opts.AllowDemo {
+			return ErrDemoNotAllowed
+		}
+		return seedLoadTest(dbConn, opts.LoadTest)
+	}
+	return fmt.Errorf("unknown seed profile %q (available: %s)", profile, strings.Join(Profiles, ", "))
+}
 
-	// 1) Admin (existing behavior)
-	adminEmail := os.Getenv("ADMIN_EMAIL")
-	adminPassword := os.Getenv("ADMIN_PASSWORD")
-	adminName := os.Getenv("ADMIN_NAME")
+// seedAdmin crea el administrador configurado si no existe. Con required las
+// credenciales son obligatorias.
+func seedAdmin(dbConn *gorm.DB, cfg config.AdminConfig, required bool) error {
+	adminEmail := cfg.Email
+	adminPassword := cfg.Password
+	adminName := cfg.Name
 	if adminEmail == "" || adminPassword == "" {
+		if required {
+			return errors.New("ADMIN_EMAIL and ADMIN_PASSWORD are required")
+		}
 		log.Warn("No se proporcionaron credenciales de admin en variables de entorno; se omitirá la creación del admin (si no existe)")
-	} else {
-		var existing models.User
-		res := dbConn.Where("email = ?", adminEmail).First(&existing)
-		if res.Error == nil && existing.ID != 0 {
-			log.Infof("Usuario admin ya existe: %s", adminEmail)
-		} else {
-			hash, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
-			if err != nil {
-				log.WithError(err).Error("Error al hashear la contraseña del admin")
-				return err
-			}
-			admin := models.User{
-				Name:         adminName,
-				Email:        adminEmail,
-				PasswordHash: string(hash),
-				Role:         "ADMIN",
-				IsConfirmed:  true,
-			}
-			if err := dbConn.Create(&admin).Error; err != nil {
-				log.WithError(err).Error("Error al crear usuario admin")
-				return err
-			}
-			log.Infof("Usuario admin creado: %s", adminEmail)
-		}
-	}
-
-	// 2) Sample users (professors and students) - ensure they exist and have hashed passwords
+		return nil
+	}
+	var existing models.User
+	res := dbConn.Where("email = ?", adminEmail).First(&existing)
+	if res.Error == nil && existing.ID != 0 {
+		log.Infof("Usuario admin ya existe: %s", adminEmail)
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.WithError(err).Error("Error al hashear la contraseña del admin")
+		return err
+	}
+	admin := models.User{
+		Name:         adminName,
+		Email:        adminEmail,
+		PasswordHash: string(hash),
+		Role:         "ADMIN",
+		IsConfirmed:  true,
+	}
+	if err := dbConn.Create(&admin).Error; err != nil {
+		log.WithError(err).Error("Error al crear usuario admin")
+		return err
+	}
+	log.Infof("Usuario admin creado: %s", adminEmail)
+	return nil
+}
+
+// seedSampleUsers crea profesores y estudiantes de ejemplo con contraseña conocida
+func seedSampleUsers(dbConn *gorm.DB) error {
 	sampleUsers := []struct {
 		Name     string
 		Email    string
@@ -99,58 +152,20 @@ func SeedAdmin() error {
 		}
 		log.Infof("Usuario de muestra creado: %s (%s)", newUser.Email, newUser.Role)
 	}
-
-	// 3) Execute embedded SQL files (buildings, rooms, etc.)
-	if err := runSQLSeeds(dbConn); err != nil {
-		return err
-	}
-
-	// 4) Create sample reservations programmatically so we can link real user IDs and room IDs
-	if err := createSampleReservations(dbConn); err != nil {
-		return err
-	}
-
 	return nil
 }
 
 func runSQLSeeds(dbConn *gorm.DB) error {
-	// Primero intentamos leer archivos embebidos (compilados). Si no existen
-	// se intentará leer desde el filesystem en /app/sql (copiado en la imagen Docker).
 	entries, err := fs.ReadDir(seedFS, "sql")
-	if err == nil {
-		for _, e := range entries {
-			if e.IsDir() {
-				continue
-			}
-			name := e.Name()
-			content, err := seedFS.ReadFile("sql/" + name)
-			if err != nil {
-				log.WithError(err).WithField("file", name).Error("Error leyendo seed SQL embebido")
-				return err
-			}
-			if err := execSQLStatements(dbConn, name, string(content)); err != nil {
-				return err
-			}
-		}
-		return nil
-	}
-
-	// Fallback a carpeta /app/sql (esta será copiada por Dockerfile)
-	log.WithField("err", err).Warn("No hay seeds embebidos; intentando /app/sql")
-	files, ferr := os.ReadDir("/app/sql")
-	if ferr != nil {
-		log.WithError(ferr).Warn("No se pudo leer /app/sql, omitiendo seeds SQL opcionales")
-		return nil // No es un error fatal, los seeds SQL son opcionales
+	if err != nil {
+		return err
 	}
-	for _, f := range files {
-		if f.IsDir() {
-			continue
-		}
-		name := f.Name()
-		content, rerr := os.ReadFile("/app/sql/" + name)
-		if rerr != nil {
-			log.WithError(rerr).WithField("file", name).Error("Error leyendo seed SQL desde /app/sql")
-			return rerr
+	for _, e := range entries {
+		name := e.Name()
+		content, err := seedFS.ReadFile("sql/" + name)
+		if err != nil {
+			log.WithError(err).WithField("file", name).Error("Error leyendo seed SQL embebido")
+			return err
 		}
 		if err := execSQLStatements(dbConn, name, string(content)); err != nil {
 			return err
@@ -181,13 +196,24 @@ func splitSQLStatements(sql string) []string {
 	parts := strings.Split(sql, ";")
 	out := make([]string, 0, len(parts))
 	for _, p := range parts {
-		if strings.TrimSpace(p) != "" {
+		if !onlyComments(p) {
 			out = append(out, p)
 		}
 	}
 	return out
 }
 
+// onlyComments indica si el fragmento no tiene más que líneas "--" o vacías
+func onlyComments(sql string) bool {
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
 func createSampleReservations(dbConn *gorm.DB) error {
 	// Find two professor users
 	var profs []models.User
@@ -219,10 +245,11 @@ func createSampleReservations(dbConn *gorm.DB) error {
 	}
 
 	for _, r := range sample {
-		// Avoid duplicates: check overlapping by exact match on start_time and room_id
+		// Evitar duplicados: la hora depende del momento de la ejecución, así que
+		// la reserva de muestra se identifica por aula, usuario y motivo
 		var existing models.Reservation
-		if err := dbConn.Where("room_id = ? AND start_time = ?", r.RoomID, r.StartTime).First(&existing).Error; err == nil && existing.ID != 0 {
-			log.Infof("Reserva de muestra ya existe para room %d start %s", r.RoomID, r.StartTime)
+		if err := dbConn.Where("room_id = ? AND user_id = ? AND purpose = ?", r.RoomID, r.UserID, r.Purpose).First(&existing).Error; err == nil && existing.ID != 0 {
+			log.Infof("Reserva de muestra ya existe para room %d: %s", r.RoomID, r.Purpose)
 			continue
 		}
 		if err := dbConn.Create(&r).Error; err != nil {
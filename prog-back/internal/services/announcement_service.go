@@ -0,0 +1,176 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var weekdayNames = []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}
+
+type AnnouncementService struct {
+	repo          *repositories.AnnouncementRepository
+	classRepo     *repositories.ClassRepository
+	roomRepo      *repositories.RoomRepository
+	notifications *NotificationService
+}
+
+func NewAnnouncementService(cfg *config.Config) *AnnouncementService {
+	return &AnnouncementService{
+		repo:          repositories.NewAnnouncementRepository(),
+		classRepo:     repositories.NewClassRepository(),
+		roomRepo:      repositories.NewRoomRepository(),
+		notifications: NewNotificationService(cfg),
+	}
+}
+
+// AnnouncementView es un aviso junto con su estado de lectura para el usuario actual
+type AnnouncementView struct {
+	models.Announcement
+	Read   bool       `json:"read"`
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
+
+func (s *AnnouncementService) Post(classID, authorID uint, title, body string) (*models.Announcement, error) {
+	class, err := s.classRepo.FindByID(classID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+	if class.ArchivedAt != nil {
+		return nil, errClassArchived
+	}
+	a := &models.Announcement{ClassID: classID, AuthorID: &authorID, Title: title, Body: body}
+	if err := s.repo.Create(a); err != nil {
+		return nil, err
+	}
+	s.notifyStudents(class, a)
+	return a, nil
+}
+
+func (s *AnnouncementService) Get(id uint) (*models.Announcement, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *AnnouncementService) Delete(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *AnnouncementService) ListForClass(classID, userID uint) ([]AnnouncementView, error) {
+	list, err := s.repo.FindByClassIDs([]uint{classID})
+	if err != nil {
+		return nil, err
+	}
+	return s.withReadState(list, userID, false)
+}
+
+// ListForUser devuelve los avisos de todas las clases en las que el usuario está inscrito
+func (s *AnnouncementService) ListForUser(userID uint, unreadOnly bool) ([]AnnouncementView, error) {
+	classes, err := s.classRepo.FindClassesByStudentID(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(classes))
+	for _, c := range classes {
+		ids = append(ids, c.ID)
+	}
+	list, err := s.repo.FindByClassIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	return s.withReadState(list, userID, unreadOnly)
+}
+
+func (s *AnnouncementService) MarkRead(id, userID uint) error {
+	return s.repo.MarkRead(id, userID, time.Now())
+}
+
+func (s *AnnouncementService) withReadState(list []models.Announcement, userID uint, unreadOnly bool) ([]AnnouncementView, error) {
+	ids := make([]uint, 0, len(list))
+	for _, a := range list {
+		ids = append(ids, a.ID)
+	}
+	reads, err := s.repo.ReadAtByUser(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]AnnouncementView, 0, len(list))
+	for _, a := range list {
+		v := AnnouncementView{Announcement: a}
+		if at, ok := reads[a.ID]; ok {
+			v.Read = true
+			v.ReadAt = &at
+		}
+		if unreadOnly && v.Read {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// postSystem publica un aviso automático. Los errores sólo se registran para
+// no hacer fallar la operación que lo originó.
+func (s *AnnouncementService) postSystem(classID uint, reservationID *uint, title, body string) {
+	a := &models.Announcement{ClassID: classID, ReservationID: reservationID, Title: title, Body: body}
+	if err := s.repo.Create(a); err != nil {
+		log.WithError(err).WithField("class_id", classID).Error("Error publicando aviso automático")
+		return
+	}
+	if class, err := s.classRepo.FindByID(classID); err == nil {
+		s.notifyStudents(class, a)
+	}
+}
+
+// notifyStudents envía el aviso a los inscritos por los canales de notificación
+func (s *AnnouncementService) notifyStudents(class *models.Class, a *models.Announcement) {
+	ids := make([]uint, 0, len(class.Students))
+	for _, st := range class.Students {
+		ids = append(ids, st.ID)
+	}
+	s.notifications.Notify(EventClassAnnouncement, ids, map[string]string{
+		"Class": class.Name,
+		"Title": a.Title,
+		"Body":  a.Body,
+	})
+}
+
+// SessionCancelled avisa a los estudiantes que una sesión puntual fue cancelada
+func (s *AnnouncementService) SessionCancelled(resv *models.Reservation) {
+	if resv.ClassID == nil {
+		return
+	}
+	when := resv.StartTime.Local().Format("02/01 15:04")
+	s.postSystem(*resv.ClassID, &resv.ID,
+		"Clase cancelada",
+		fmt.Sprintf("La clase del %s en %s fue cancelada.", when, s.roomName(resv.RoomID)))
+}
+
+// ScheduleChanged avisa un cambio de día, horario o aula de una sesión semanal
+func (s *AnnouncementService) ScheduleChanged(old, updated *models.ClassSession) {
+	s.postSystem(updated.ClassID, nil,
+		"Cambio de horario",
+		fmt.Sprintf("Las clases de los %s %s en %s pasan a los %s %s en %s.",
+			weekdayNames[old.Weekday], old.StartTime, s.roomName(old.RoomID),
+			weekdayNames[updated.Weekday], updated.StartTime, s.roomName(updated.RoomID)))
+}
+
+// ScheduleRemoved avisa que una sesión semanal dejó de dictarse
+func (s *AnnouncementService) ScheduleRemoved(sess *models.ClassSession) {
+	s.postSystem(sess.ClassID, nil,
+		"Clases canceladas",
+		fmt.Sprintf("Se cancelan las clases de los %s %s en %s.",
+			weekdayNames[sess.Weekday], sess.StartTime, s.roomName(sess.RoomID)))
+}
+
+func (s *AnnouncementService) roomName(roomID uint) string {
+	if room, err := s.roomRepo.GetByID(roomID); err == nil {
+		return room.Name
+	}
+	return fmt.Sprintf("aula %d", roomID)
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+)
+
+const (
+	// Cada cuánto rota el código que proyecta el profesor
+	attendanceCodePeriod = 30 * time.Second
+	// Pasado este margen desde el inicio la asistencia se registra como LATE
+	attendanceLateAfter = 10 * time.Minute
+)
+
+var attendanceStatuses = map[string]bool{"PRESENT": true, "LATE": true, "ABSENT": true, "EXCUSED": true}
+
+type AttendanceService struct {
+	repo      *repositories.AttendanceRepository
+	resvRepo  *repositories.ReservationRepository
+	classRepo *repositories.ClassRepository
+	secret    []byte // firma los códigos rotativos (el mismo secreto que los JWT)
+}
+
+func NewAttendanceService(cfg *config.Config) *AttendanceService {
+	return &AttendanceService{
+		repo:      repositories.NewAttendanceRepository(),
+		resvRepo:  repositories.NewReservationRepository(),
+		classRepo: repositories.NewClassRepository(),
+		secret:    []byte(cfg.Auth.JWTSecret),
+	}
+}
+
+// ClassSession devuelve la reserva si corresponde a una sesión de clase
+func (s *AttendanceService) ClassSession(reservationID uint) (*models.Reservation, error) {
+	resv, err := s.resvRepo.GetByID(reservationID)
+	if err != nil {
+		return nil, errors.New("reservation not found")
+	}
+	if resv.ClassID == nil {
+		return nil, errors.New("reservation is not a class session")
+	}
+	return resv, nil
+}
+
+// CurrentCode devuelve el código rotativo vigente de la sesión y su vencimiento
+func (s *AttendanceService) CurrentCode(reservationID uint) (string, time.Time) {
+	step := time.Now().Unix() / int64(attendanceCodePeriod.Seconds())
+	expires := time.Unix((step+1)*int64(attendanceCodePeriod.Seconds()), 0)
+	return s.code(reservationID, step), expires
+}
+
+// SelfCheckIn registra la asistencia del estudiante con el código proyectado
+func (s *AttendanceService) SelfCheckIn(reservationID, studentID uint, code string) (*models.Attendance, error) {
+	resv, err := s.ClassSession(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEnrolled(*resv.ClassID, studentID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if resv.Status != "ACTIVE" || now.Before(resv.StartTime.Add(-checkinEarlyWindow)) || !now.Before(resv.EndTime) {
+		return nil, errors.New("session is not in progress")
+	}
+	// Se acepta también el código del período anterior por si rotó mientras se escribía
+	step := now.Unix() / int64(attendanceCodePeriod.Seconds())
+	if !hmac.Equal([]byte(code), []byte(s.code(reservationID, step))) &&
+		!hmac.Equal([]byte(code), []byte(s.code(reservationID, step-1))) {
+		return nil, errors.New("invalid or expired code")
+	}
+
+	if existing, err := s.repo.Get(reservationID, studentID); err == nil &&
+		(existing.Status == "PRESENT" || existing.Status == "LATE") {
+		return existing, nil
+	}
+	status := "PRESENT"
+	if now.After(resv.StartTime.Add(attendanceLateAfter)) {
+		status = "LATE"
+	}
+	a := &models.Attendance{ReservationID: reservationID, StudentID: studentID, Status: status, MarkedByID: &studentID}
+	if err := s.repo.Upsert(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetStatus permite al profesor registrar o corregir la asistencia manualmente
+func (s *AttendanceService) SetStatus(reservationID, studentID uint, status, note string, markedBy uint) (*models.Attendance, error) {
+	if !attendanceStatuses[status] {
+		return nil, errors.New("invalid status")
+	}
+	resv, err := s.ClassSession(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEnrolled(*resv.ClassID, studentID); err != nil {
+		return nil, err
+	}
+	a := &models.Attendance{ReservationID: reservationID, StudentID: studentID, Status: status, Note: note, MarkedByID: &markedBy}
+	if err := s.repo.Upsert(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+type AttendanceEntry struct {
+	Student models.User `json:"student"`
+	Status  string      `json:"status"` // UNMARKED si no hay registro
+	Note    string      `json:"note,omitempty"`
+}
+
+// ListForSession devuelve el padrón de la clase con el estado de cada estudiante
+func (s *AttendanceService) ListForSession(reservationID uint) ([]AttendanceEntry, error) {
+	resv, err := s.ClassSession(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	students, err := s.classRepo.GetStudents(*resv.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.ListByReservation(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint]models.Attendance, len(records))
+	for _, a := range records {
+		byStudent[a.StudentID] = a
+	}
+	out := make([]AttendanceEntry, 0, len(students))
+	for _, st := range students {
+		e := AttendanceEntry{Student: st, Status: "UNMARKED"}
+		if a, ok := byStudent[st.ID]; ok {
+			e.Status = a.Status
+			e.Note = a.Note
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+type StudentAttendance struct {
+	StudentID    uint    `json:"student_id"`
+	Name         string  `json:"name"`
+	Email        string  `json:"email"`
+	SessionsHeld int64   `json:"sessions_held"` // desde su inscripción
+	Present      int64   `json:"present"`
+	Late         int64   `json:"late"`
+	Absent       int64   `json:"absent"`
+	Excused      int64   `json:"excused"`
+	Rate         float64 `json:"attendance_rate"` // (present + late) / sesiones dictadas desde su inscripción
+}
+
+type ClassAttendanceReport struct {
+	ClassID      uint                `json:"class_id"`
+	SessionsHeld int                 `json:"sessions_held"`
+	Rate         float64             `json:"attendance_rate"`
+	Students     []StudentAttendance `json:"students"`
+}
+
+// ClassReport calcula las tasas de asistencia por estudiante y de la clase.
+// Las sesiones ya comenzadas sin registro cuentan como ausencia.
+func (s *AttendanceService) ClassReport(classID uint) (*ClassAttendanceReport, error) {
+	held, err := s.repo.HeldSessions(classID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	roster, err := s.classRepo.GetRoster(classID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(held))
+	for i, h := range held {
+		ids[i] = h.ID
+	}
+	marks, err := s.repo.ListMarks(ids)
+	if err != nil {
+		return nil, err
+	}
+	return buildClassReport(classID, held, roster, marks), nil
+}
+
+// buildClassReport arma el reporte de los inscriptos. A cada estudiante sólo
+// se le cuentan las sesiones que comenzaron a partir de su inscripción.
+func buildClassReport(classID uint, held []repositories.HeldSession, roster []repositories.RosterEntry, marks []repositories.AttendanceMark) *ClassAttendanceReport {
+	statuses := map[uint]map[uint]string{} // estudiante -> sesión -> estado
+	for _, m := range marks {
+		if statuses[m.StudentID] == nil {
+			statuses[m.StudentID] = map[uint]string{}
+		}
+		statuses[m.StudentID][m.ReservationID] = m.Status
+	}
+
+	report := &ClassAttendanceReport{ClassID: classID, SessionsHeld: len(held), Students: []StudentAttendance{}}
+	var attended, expected int64
+	for _, st := range roster {
+		if st.Status != "ENROLLED" {
+			continue
+		}
+		sa := StudentAttendance{StudentID: st.StudentID, Name: st.Name, Email: st.Email}
+		for _, h := range held {
+			if h.StartTime.Before(st.EnrolledAt) {
+				continue
+			}
+			sa.SessionsHeld++
+			switch statuses[st.StudentID][h.ID] {
+			case "PRESENT":
+				sa.Present++
+			case "LATE":
+				sa.Late++
+			case "EXCUSED":
+				sa.Excused++
+			default:
+				sa.Absent++
+			}
+		}
+		if sa.SessionsHeld > 0 {
+			sa.Rate = float64(sa.Present+sa.Late) / float64(sa.SessionsHeld)
+		}
+		attended += sa.Present + sa.Late
+		expected += sa.SessionsHeld
+		report.Students = append(report.Students, sa)
+	}
+	if expected > 0 {
+		report.Rate = float64(attended) / float64(expected)
+	}
+	return report
+}
+
+func (s *AttendanceService) checkEnrolled(classID, studentID uint) error {
+	cs, err := s.classRepo.GetEnrollment(classID, studentID)
+	if err != nil || cs.Status != "ENROLLED" {
+		return errors.New("student not enrolled in class")
+	}
+	return nil
+}
+
+// code deriva un código numérico de 6 dígitos (estilo TOTP) para la reserva
+// y el período dado
+func (s *AttendanceService) code(reservationID uint, step int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "attendance:%d:%d", reservationID, step)
+	sum := mac.Sum(nil)
+	return fmt.Sprintf("%06d", binary.BigEndian.Uint32(sum[:4])%1000000)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"programcion-backend/internal/repositories"
+)
+
+func TestBuildClassReport(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 10, 0, 0, 0, time.UTC) }
+	held := []repositories.HeldSession{{ID: 1, StartTime: day(2)}, {ID: 2, StartTime: day(9)}, {ID: 3, StartTime: day(16)}, {ID: 4, StartTime: day(23)}}
+	roster := []repositories.RosterEntry{
+		{StudentID: 10, Name: "Ana", Status: "ENROLLED", EnrolledAt: day(1)},
+		// Se inscribe justo al comenzar la tercera sesión: le cuentan la 3 y la 4
+		{StudentID: 11, Name: "Luis", Status: "ENROLLED", EnrolledAt: day(16)},
+		{StudentID: 12, Name: "Eva", Status: "WAITLISTED", EnrolledAt: day(1)},
+		{StudentID: 13, Name: "Sol", Status: "ENROLLED", EnrolledAt: day(24)},
+	}
+	marks := []repositories.AttendanceMark{
+		{ReservationID: 1, StudentID: 10, Status: "PRESENT"},
+		{ReservationID: 2, StudentID: 10, Status: "LATE"},
+		{ReservationID: 3, StudentID: 10, Status: "EXCUSED"},
+		// Registro de una sesión anterior a su inscripción: no cuenta
+		{ReservationID: 1, StudentID: 11, Status: "PRESENT"},
+		{ReservationID: 3, StudentID: 11, Status: "PRESENT"},
+		{ReservationID: 4, StudentID: 11, Status: "ABSENT"},
+	}
+
+	got := buildClassReport(5, held, roster, marks)
+	if got.SessionsHeld != 4 {
+		t.Errorf("SessionsHeld = %d, want 4", got.SessionsHeld)
+	}
+	want := []StudentAttendance{
+		{StudentID: 10, Name: "Ana", SessionsHeld: 4, Present: 1, Late: 1, Excused: 1, Absent: 1, Rate: 0.5},
+		{StudentID: 11, Name: "Luis", SessionsHeld: 2, Present: 1, Absent: 1, Rate: 0.5},
+		{StudentID: 13, Name: "Sol"},
+	}
+	if len(got.Students) != len(want) {
+		t.Fatalf("students = %+v, want %+v", got.Students, want)
+	}
+	for i := range want {
+		if got.Students[i] != want[i] {
+			t.Errorf("student %d = %+v, want %+v", i, got.Students[i], want[i])
+		}
+	}
+	// (2 + 1) asistencias sobre (4 + 2) sesiones esperadas
+	if got.Rate != 0.5 {
+		t.Errorf("Rate = %v, want 0.5", got.Rate)
+	}
+}
@@ -1,23 +1,34 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
 	"programcion-backend/internal/models"
 	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
 	"programcion-backend/pkg/utils"
 
 	"github.com/golang-jwt/jwt/v5"
+	log "github.com/sirupsen/logrus"
 )
 
 type AuthService struct {
-	repo *repositories.UserRepository
+	repo          *repositories.UserRepository
+	classes       *ClassService
+	notifications *NotificationService
+	jwtSecret     []byte
 }
 
-func NewAuthService() *AuthService {
-	return &AuthService{repo: repositories.NewUserRepository()}
+func NewAuthService(cfg *config.Config) *AuthService {
+	return &AuthService{
+		repo:          repositories.NewUserRepository(),
+		classes:       NewClassService(cfg),
+		notifications: NewNotificationService(cfg),
+		jwtSecret:     []byte(cfg.Auth.JWTSecret),
+	}
 }
 
 func (s *AuthService) Register(name, email, password, role string) (*models.User, error) {
@@ -52,17 +63,13 @@ func (s *AuthService) Login(email, password string) (string, error) {
 	}
 
 	// generar JWT
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "dev_secret"
-	}
 	expHours := 24
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub":  u.ID,
 		"role": u.Role,
 		"exp":  time.Now().Add(time.Duration(expHours) * time.Hour).Unix(),
 	})
-	tokStr, err := token.SignedString([]byte(secret))
+	tokStr, err := token.SignedString(s.jwtSecret)
 	if err != nil {
 		return "", err
 	}
@@ -82,17 +89,13 @@ func (s *AuthService) LoginWithUser(email, password string) (string, *models.Use
 	}
 
 	// generar JWT
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "dev_secret"
-	}
 	expHours := 24
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub":  u.ID,
 		"role": u.Role,
 		"exp":  time.Now().Add(time.Duration(expHours) * time.Hour).Unix(),
 	})
-	tokStr, err := token.SignedString([]byte(secret))
+	tokStr, err := token.SignedString(s.jwtSecret)
 	if err != nil {
 		return "", nil, err
 	}
@@ -104,8 +107,77 @@ func (s *AuthService) ConfirmUser(id uint) error {
 	if err != nil {
 		return err
 	}
-	u.IsConfirmed = true
-	return s.repo.Update(u)
+	// Una cuenta ya confirmada reintenta la inscripción pendiente, si la hay
+	if !u.IsConfirmed {
+		u.IsConfirmed = true
+		if err := s.repo.Update(u); err != nil {
+			return err
+		}
+		s.notifications.Notify(EventAccountConfirmed, []uint{u.ID}, map[string]string{"Name": u.Name})
+	}
+	return s.enrollInvited(u)
+}
+
+// Vigencia del código de invitación de las cuentas creadas desde un padrón
+const inviteTTL = 7 * 24 * time.Hour
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AcceptInvite fija la contraseña de una cuenta invitada. El código se
+// consume al usarlo; la cuenta sigue sujeta a la confirmación del admin y
+// sólo se inscribe en la clase del padrón si ya está confirmada.
+func (s *AuthService) AcceptInvite(token, password string) error {
+	invite, err := s.repo.FindInviteByHash(hashInviteToken(token))
+	if err != nil || time.Now().After(invite.ExpiresAt) {
+		return errors.New("invalid or expired invitation")
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.AcceptInvite(invite, hash); err != nil {
+		return errors.New("invalid or expired invitation")
+	}
+	u, err := s.repo.GetByID(invite.UserID)
+	if err != nil {
+		return err
+	}
+	return s.enrollInvited(u)
+}
+
+// invitedClass devuelve la clase en la que falta inscribir a la cuenta
+// invitada, sólo cuando ya puede inscribirse (cuenta confirmada)
+func invitedClass(u *models.User, invite *models.UserInvite) (uint, bool) {
+	if invite == nil || invite.ClassID == nil || !u.IsConfirmed {
+		return 0, false
+	}
+	return *invite.ClassID, true
+}
+
+// enrollInvited inscribe a la cuenta en la clase del padrón desde el que se
+// la invitó. Si la clase ya no admite la inscripción se descarta; ante otros
+// errores queda pendiente y se reintenta al volver a confirmar.
+func (s *AuthService) enrollInvited(u *models.User) error {
+	invite, err := s.repo.FindInviteByUser(u.ID)
+	if err != nil {
+		return err
+	}
+	classID, ok := invitedClass(u, invite)
+	if !ok {
+		return nil
+	}
+	_, err = s.classes.AddStudent(classID, u.ID)
+	switch {
+	case err == nil:
+	case errors.Is(err, errClassNotFound), errors.Is(err, errClassArchived), errors.Is(err, errNotAStudent):
+		log.WithError(err).WithField("class_id", classID).Warn("dropping pending enrolment of invited user")
+	default:
+		return err
+	}
+	return s.repo.ClearInviteClass(invite)
 }
 
 func (s *AuthService) GetUserByID(id uint) (*models.User, error) {
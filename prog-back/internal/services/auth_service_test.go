@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"programcion-backend/internal/models"
+)
+
+func TestInvitedClass(t *testing.T) {
+	classID := uint(7)
+	accepted := time.Now()
+	tests := []struct {
+		name      string
+		confirmed bool
+		invite    *models.UserInvite
+		want      uint
+		wantOK    bool
+	}{
+		{"confirmada con clase pendiente", true, &models.UserInvite{ClassID: &classID}, 7, true},
+		{"confirmada tras aceptar la invitación", true, &models.UserInvite{ClassID: &classID, AcceptedAt: &accepted}, 7, true},
+		{"aceptada pero sin confirmar", false, &models.UserInvite{ClassID: &classID, AcceptedAt: &accepted}, 0, false},
+		{"sin confirmar", false, &models.UserInvite{ClassID: &classID}, 0, false},
+		{"inscripción ya resuelta", true, &models.UserInvite{}, 0, false},
+		{"sin invitación", true, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &models.User{ID: 1, Role: "STUDENT", IsConfirmed: tt.confirmed}
+			got, ok := invitedClass(u, tt.invite)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("invitedClass() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -2,28 +2,57 @@ package services
 
 import (
 	"errors"
+	"time"
+
 	"programcion-backend/internal/models"
 	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+	"programcion-backend/pkg/utils"
 )
 
 type ClassService struct {
-	repo     *repositories.ClassRepository
-	userRepo *repositories.UserRepository
+	repo          *repositories.ClassRepository
+	userRepo      *repositories.UserRepository
+	termRepo      *repositories.TermRepository
+	sessionRepo   *repositories.ClassSessionRepository
+	notifications *NotificationService
 }
 
-func NewClassService() *ClassService {
+func NewClassService(cfg *config.Config) *ClassService {
 	return &ClassService{
-		repo:     repositories.NewClassRepository(),
-		userRepo: repositories.NewUserRepository(),
+		repo:          repositories.NewClassRepository(),
+		userRepo:      repositories.NewUserRepository(),
+		termRepo:      repositories.NewTermRepository(),
+		sessionRepo:   repositories.NewClassSessionRepository(),
+		notifications: NewNotificationService(cfg),
 	}
 }
 
-func (s *ClassService) CreateClass(name, description, subject string, professorID uint) (*models.Class, error) {
+var (
+	errClassNotFound      = errors.New("class not found")
+	errClassArchived      = errors.New("class is archived")
+	errStudentNotFound    = errors.New("student not found")
+	errNotAStudent        = errors.New("user is not a student")
+	errStudentUnconfirmed = errors.New("student account not confirmed")
+)
+
+func (s *ClassService) CreateClass(name, description, subject string, professorID uint, termID *uint, maxStudents int) (*models.Class, error) {
+	if termID != nil {
+		term, err := s.termRepo.GetByID(*termID)
+		if err != nil {
+			return nil, errors.New("term not found")
+		}
+		if term.ArchivedAt != nil {
+			return nil, errors.New("term is archived")
+		}
+	}
 	class := &models.Class{
 		Name:        name,
 		Description: description,
 		Subject:     subject,
 		ProfessorID: professorID,
+		TermID:      termID,
+		MaxStudents: maxStudents,
 	}
 	if err := s.repo.Create(class); err != nil {
 		return nil, err
@@ -36,23 +65,26 @@ func (s *ClassService) GetClass(id uint) (*models.Class, error) {
 	return s.repo.FindByID(id)
 }
 
-func (s *ClassService) GetClassesByProfessor(professorID uint) ([]models.Class, error) {
-	return s.repo.FindByProfessorID(professorID)
+func (s *ClassService) GetClassesByProfessor(professorID, termID uint) ([]models.Class, error) {
+	return s.repo.FindByProfessorID(professorID, termID)
 }
 
-func (s *ClassService) GetClassesByStudent(studentID uint) ([]models.Class, error) {
-	return s.repo.FindClassesByStudentID(studentID)
+func (s *ClassService) GetClassesByStudent(studentID, termID uint) ([]models.Class, error) {
+	return s.repo.FindClassesByStudentID(studentID, termID)
 }
 
-func (s *ClassService) GetAllClasses() ([]models.Class, error) {
-	return s.repo.FindAll()
+func (s *ClassService) GetAllClasses(termID uint) ([]models.Class, error) {
+	return s.repo.FindAll(termID)
 }
 
-func (s *ClassService) UpdateClass(id uint, name, description, subject string) (*models.Class, error) {
+func (s *ClassService) UpdateClass(id uint, name, description, subject string, maxStudents *int) (*models.Class, error) {
 	class, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if class.ArchivedAt != nil {
+		return nil, errClassArchived
+	}
 
 	if name != "" {
 		class.Name = name
@@ -63,6 +95,9 @@ func (s *ClassService) UpdateClass(id uint, name, description, subject string) (
 	if subject != "" {
 		class.Subject = subject
 	}
+	if maxStudents != nil {
+		class.MaxStudents = *maxStudents
+	}
 
 	if err := s.repo.Update(class); err != nil {
 		return nil, err
@@ -71,39 +106,266 @@ func (s *ClassService) UpdateClass(id uint, name, description, subject string) (
 	return class, nil
 }
 
+// DeleteClass borra la clase de forma lógica, cancela sus reservas futuras y
+// archiva sus inscripciones
 func (s *ClassService) DeleteClass(id uint) error {
 	return s.repo.Delete(id)
 }
 
-func (s *ClassService) AddStudent(classID, studentID uint) error {
-	// Verificar que el estudiante existe y es STUDENT
+func (s *ClassService) ListDeletedClasses() ([]models.Class, error) {
+	return s.repo.FindDeleted()
+}
+
+// RestoreClass recupera una clase borrada con sus inscripciones archivadas.
+// Las reservas canceladas al borrarla no se recrean.
+func (s *ClassService) RestoreClass(id uint) error {
+	class, err := s.repo.FindDeletedByID(id)
+	if err != nil {
+		return errors.New("deleted class not found")
+	}
+	if _, err := s.userRepo.GetByID(class.ProfessorID); err != nil {
+		return errors.New("class professor no longer exists")
+	}
+	return s.repo.Restore(id)
+}
+
+// PurgeClass elimina definitivamente una clase que ya fue borrada
+func (s *ClassService) PurgeClass(id uint) error {
+	if _, err := s.repo.FindDeletedByID(id); err != nil {
+		return errors.New("deleted class not found")
+	}
+	return s.repo.Purge(id)
+}
+
+// AddStudent inscribe al estudiante o lo deja en lista de espera si el cupo
+// está completo. Devuelve el estado final (ENROLLED|WAITLISTED).
+func (s *ClassService) AddStudent(classID, studentID uint) (string, error) {
+	if err := s.ensureWritable(classID); err != nil {
+		return "", err
+	}
+	if err := s.checkStudent(studentID); err != nil {
+		return "", err
+	}
+	return s.enroll(classID, studentID)
+}
+
+func (s *ClassService) enroll(classID, studentID uint) (string, error) {
+	capacity, err := s.effectiveCapacity(classID)
+	if err != nil {
+		return "", err
+	}
+	previous := ""
+	if cs, err := s.repo.GetEnrollment(classID, studentID); err == nil {
+		previous = cs.Status
+	}
+	status, err := s.repo.AddStudent(classID, studentID, capacity)
+	if err != nil || status == previous {
+		return status, err
+	}
+	event := EventClassEnrolled
+	if status == "WAITLISTED" {
+		event = EventClassWaitlisted
+	}
+	s.notifyEnrollment(event, classID, studentID)
+	return status, nil
+}
+
+func (s *ClassService) notifyEnrollment(event string, classID, studentID uint) {
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return
+	}
+	s.notifications.Notify(event, []uint{studentID}, map[string]string{"Class": class.Name})
+}
+
+// effectiveCapacity es el cupo real de la clase: el menor entre max_students
+// y la capacidad de las aulas de sus sesiones (0 = sin límite)
+func (s *ClassService) effectiveCapacity(classID uint) (int, error) {
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return 0, errors.New("class not found")
+	}
+	capacity := class.MaxStudents
+	sessions, err := s.sessionRepo.FindByClassID(classID)
+	if err != nil {
+		return 0, err
+	}
+	for _, sess := range sessions {
+		if sess.Room != nil && (capacity == 0 || sess.Room.Capacity < capacity) {
+			capacity = sess.Room.Capacity
+		}
+	}
+	return capacity, nil
+}
+
+func (s *ClassService) GetWaitlist(classID uint) ([]models.User, error) {
+	return s.repo.GetWaitlist(classID)
+}
+
+// Verificar que el estudiante existe, es STUDENT y está confirmado
+func (s *ClassService) checkStudent(studentID uint) error {
 	student, err := s.userRepo.GetByID(studentID)
 	if err != nil {
-		return errors.New("student not found")
+		return errStudentNotFound
 	}
 	if student.Role != "STUDENT" {
-		return errors.New("user is not a student")
+		return errNotAStudent
 	}
 	if !student.IsConfirmed {
-		return errors.New("student account not confirmed")
+		return errStudentUnconfirmed
 	}
+	return nil
+}
 
-	return s.repo.AddStudent(classID, studentID)
+type JoinSettings struct {
+	JoinCode         string `json:"join_code"`
+	Enabled          bool   `json:"enabled"`
+	RequiresApproval bool   `json:"requires_approval"`
 }
 
-func (s *ClassService) RemoveStudent(classID, studentID uint) error {
-	return s.repo.RemoveStudent(classID, studentID)
+func (s *ClassService) GetJoinSettings(classID uint) (*JoinSettings, error) {
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+	js := &JoinSettings{Enabled: class.JoinEnabled, RequiresApproval: class.JoinRequiresApproval}
+	if class.JoinCode != nil {
+		js.JoinCode = *class.JoinCode
+	}
+	return js, nil
 }
 
-func (s *ClassService) GetClassStudents(classID uint) ([]models.User, error) {
-	return s.repo.GetStudents(classID)
+// RegenerateJoinCode crea un nuevo código de inscripción (el anterior deja de
+// funcionar) y habilita la auto-inscripción
+func (s *ClassService) RegenerateJoinCode(classID uint, requiresApproval bool) (*JoinSettings, error) {
+	if err := s.ensureWritable(classID); err != nil {
+		return nil, err
+	}
+	code, err := utils.GenerateCode(8)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetJoinSettings(classID, &code, true, requiresApproval); err != nil {
+		return nil, err
+	}
+	return &JoinSettings{JoinCode: code, Enabled: true, RequiresApproval: requiresApproval}, nil
 }
 
-// Verificar si un usuario es dueño de una clase
-func (s *ClassService) IsOwner(classID, userID uint) (bool, error) {
+func (s *ClassService) DisableJoinCode(classID uint) error {
 	class, err := s.repo.FindByID(classID)
 	if err != nil {
-		return false, err
+		return errors.New("class not found")
+	}
+	return s.repo.SetJoinSettings(classID, class.JoinCode, false, class.JoinRequiresApproval)
+}
+
+// JoinByCode inscribe al estudiante en la clase del código. Si la clase
+// requiere aprobación la inscripción queda PENDING. Devuelve el estado final.
+func (s *ClassService) JoinByCode(code string, studentID uint) (*models.Class, string, error) {
+	class, err := s.repo.FindByJoinCode(code)
+	if err != nil || !class.JoinEnabled {
+		return nil, "", errors.New("invalid or disabled join code")
+	}
+	if class.ArchivedAt != nil {
+		return nil, "", errClassArchived
+	}
+	if err := checkEnrollmentWindow(class.Term, time.Now()); err != nil {
+		return nil, "", err
+	}
+	if err := s.checkStudent(studentID); err != nil {
+		return nil, "", err
+	}
+	if existing, err := s.repo.GetEnrollment(class.ID, studentID); err == nil {
+		if existing.Status == "ENROLLED" {
+			return nil, "", errors.New("already enrolled")
+		}
+		return class, existing.Status, nil
+	}
+
+	status := "PENDING"
+	if class.JoinRequiresApproval {
+		err = s.repo.RequestEnrollment(class.ID, studentID)
+	} else {
+		status, err = s.enroll(class.ID, studentID)
+	}
+	if err != nil {
+		return nil, "", err
 	}
-	return class.ProfessorID == userID, nil
+	return class, status, nil
+}
+
+func (s *ClassService) GetPendingStudents(classID uint) ([]models.User, error) {
+	return s.repo.GetPendingStudents(classID)
+}
+
+// ApproveEnrollment aprueba una solicitud; si no hay cupo pasa a lista de espera
+func (s *ClassService) ApproveEnrollment(classID, studentID uint) (string, error) {
+	if err := s.ensureWritable(classID); err != nil {
+		return "", err
+	}
+	cs, err := s.repo.GetEnrollment(classID, studentID)
+	if err != nil || cs.Status != "PENDING" {
+		return "", errors.New("enrollment request not found")
+	}
+	return s.enroll(classID, studentID)
+}
+
+func (s *ClassService) RejectEnrollment(classID, studentID uint) error {
+	cs, err := s.repo.GetEnrollment(classID, studentID)
+	if err != nil || cs.Status != "PENDING" {
+		return errors.New("enrollment request not found")
+	}
+	// Una solicitud pendiente no ocupa cupo: no hay nada que promover
+	_, err = s.repo.RemoveStudent(classID, studentID, 0)
+	return err
+}
+
+// checkEnrollmentWindow verifica que la ventana de inscripción del período esté abierta
+func checkEnrollmentWindow(term *models.Term, now time.Time) error {
+	if term == nil {
+		return nil
+	}
+	if term.EnrollmentStart != nil && now.Before(*term.EnrollmentStart) {
+		return errors.New("enrollment window not open yet")
+	}
+	if term.EnrollmentEnd != nil && now.After(*term.EnrollmentEnd) {
+		return errors.New("enrollment window closed")
+	}
+	return nil
+}
+
+// RemoveStudent da de baja al estudiante y promueve la lista de espera hasta
+// completar el cupo. Devuelve los ids de los estudiantes promovidos.
+func (s *ClassService) RemoveStudent(classID, studentID uint) ([]uint, error) {
+	if err := s.ensureWritable(classID); err != nil {
+		return nil, err
+	}
+	capacity, err := s.effectiveCapacity(classID)
+	if err != nil {
+		return nil, err
+	}
+	promoted, err := s.repo.RemoveStudent(classID, studentID, capacity)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range promoted {
+		s.notifyEnrollment(EventClassWaitlistPromoted, classID, id)
+	}
+	return promoted, nil
+}
+
+// ensureWritable rechaza modificaciones sobre clases archivadas
+func (s *ClassService) ensureWritable(classID uint) error {
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return errClassNotFound
+	}
+	if class.ArchivedAt != nil {
+		return errClassArchived
+	}
+	return nil
+}
+
+func (s *ClassService) GetClassStudents(classID uint) ([]models.User, error) {
+	return s.repo.GetStudents(classID)
 }
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+)
+
+type ClassSessionService struct {
+	repo      *repositories.ClassSessionRepository
+	classRepo *repositories.ClassRepository
+	resvRepo  *repositories.ReservationRepository
+	roomRepo  *repositories.RoomRepository
+	termRepo  *repositories.TermRepository
+
+	announcements *AnnouncementService
+	people        *personConflictChecker
+}
+
+func NewClassSessionService(cfg *config.Config) *ClassSessionService {
+	return &ClassSessionService{
+		repo:      repositories.NewClassSessionRepository(),
+		classRepo: repositories.NewClassRepository(),
+		resvRepo:  repositories.NewReservationRepository(),
+		roomRepo:  repositories.NewRoomRepository(),
+		termRepo:  repositories.NewTermRepository(),
+
+		announcements: NewAnnouncementService(cfg),
+		people:        newPersonConflictChecker(cfg.Reservations),
+	}
+}
+
+func (s *ClassSessionService) List(classID uint) ([]models.ClassSession, error) {
+	return s.repo.FindByClassID(classID)
+}
+
+// Create define una nueva sesión semanal y genera sus reservas para el resto
+// del período. Si algún horario está ocupado no se crea nada y se devuelven
+// las reservas en conflicto.
+func (s *ClassSessionService) Create(sess *models.ClassSession) ([]models.Reservation, error) {
+	class, err := s.writableClass(sess.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	resvs, conflicts, err := s.plan(class, sess, time.Now())
+	if err != nil || len(conflicts) > 0 {
+		return conflicts, err
+	}
+	return nil, s.repo.CreateWithReservations(sess, resvs)
+}
+
+// CreateMany crea varias sesiones de forma atómica: si alguna choca con
+// reservas existentes no se crea ninguna
+func (s *ClassSessionService) CreateMany(sessions []models.ClassSession) ([]models.Reservation, error) {
+	now := time.Now()
+	all := make([][]models.Reservation, len(sessions))
+	var conflicts []models.Reservation
+	for i := range sessions {
+		class, err := s.writableClass(sessions[i].ClassID)
+		if err != nil {
+			return nil, err
+		}
+		resvs, c, err := s.plan(class, &sessions[i], now)
+		if err != nil {
+			return nil, err
+		}
+		all[i] = resvs
+		conflicts = append(conflicts, c...)
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+	return nil, s.repo.CreateManyWithReservations(sessions, all)
+}
+
+// Update modifica la sesión: cancela las reservas futuras que generó y las
+// vuelve a generar con el nuevo horario
+func (s *ClassSessionService) Update(sess *models.ClassSession) ([]models.Reservation, error) {
+	class, err := s.writableClass(sess.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	old, err := s.repo.GetByID(sess.ID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	now := time.Now()
+	resvs, conflicts, err := s.plan(class, sess, now)
+	if err != nil || len(conflicts) > 0 {
+		return conflicts, err
+	}
+	if err := s.repo.UpdateWithReservations(sess, now, resvs); err != nil {
+		return nil, err
+	}
+	if old.Weekday != sess.Weekday || old.StartTime != sess.StartTime || old.EndTime != sess.EndTime || old.RoomID != sess.RoomID {
+		s.announcements.ScheduleChanged(old, sess)
+	}
+	return nil, nil
+}
+
+// Delete elimina la sesión y cancela sus reservas futuras
+func (s *ClassSessionService) Delete(classID, sessionID uint) error {
+	sess, err := s.repo.GetByID(sessionID)
+	if err != nil || sess.ClassID != classID {
+		return errors.New("session not found")
+	}
+	if _, err := s.writableClass(classID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteWithReservations(sessionID, time.Now()); err != nil {
+		return err
+	}
+	s.announcements.ScheduleRemoved(sess)
+	return nil
+}
+
+func (s *ClassSessionService) Get(classID, sessionID uint) (*models.ClassSession, error) {
+	sess, err := s.repo.GetByID(sessionID)
+	if err != nil || sess.ClassID != classID {
+		return nil, errors.New("session not found")
+	}
+	return sess, nil
+}
+
+func (s *ClassSessionService) writableClass(classID uint) (*models.Class, error) {
+	class, err := s.classRepo.FindByID(classID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+	if class.ArchivedAt != nil {
+		return nil, errClassArchived
+	}
+	if class.TermID == nil {
+		return nil, errors.New("class has no term")
+	}
+	return class, nil
+}
+
+// plan genera las reservas semanales de la sesión desde from hasta el final
+// del período, omitiendo feriados, y detecta solapamientos con otras reservas
+func (s *ClassSessionService) plan(class *models.Class, sess *models.ClassSession, from time.Time) ([]models.Reservation, []models.Reservation, error) {
+	if sess.Weekday < 0 || sess.Weekday > 6 {
+		return nil, nil, errors.New("weekday must be between 0 and 6")
+	}
+	startClock, err := time.Parse("15:04", sess.StartTime)
+	if err != nil {
+		return nil, nil, errors.New("invalid start_time format")
+	}
+	endClock, err := time.Parse("15:04", sess.EndTime)
+	if err != nil {
+		return nil, nil, errors.New("invalid end_time format")
+	}
+	if !endClock.After(startClock) {
+		return nil, nil, errors.New("end_time must be after start_time")
+	}
+
+	room, err := s.roomRepo.GetByID(sess.RoomID)
+	if err != nil {
+		return nil, nil, errors.New("room not found")
+	}
+	if len(class.Students) > room.Capacity {
+		return nil, nil, errors.New("enrolled students exceed room capacity")
+	}
+	term, err := s.termRepo.GetByID(*class.TermID)
+	if err != nil {
+		return nil, nil, errors.New("term not found")
+	}
+
+	var resvs, conflicts []models.Reservation
+	var personConflicts []models.ReservationConflict
+	first := time.Date(term.StartDate.Year(), term.StartDate.Month(), term.StartDate.Day(), 0, 0, 0, 0, time.Local)
+	last := time.Date(term.EndDate.Year(), term.EndDate.Month(), term.EndDate.Day(), 0, 0, 0, 0, time.Local)
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if int(d.Weekday()) != sess.Weekday || isHoliday(term, d) {
+			continue
+		}
+		st := time.Date(d.Year(), d.Month(), d.Day(), startClock.Hour(), startClock.Minute(), 0, 0, time.Local)
+		et := time.Date(d.Year(), d.Month(), d.Day(), endClock.Hour(), endClock.Minute(), 0, 0, time.Local)
+		if st.Before(from) {
+			continue
+		}
+		overlapping, err := s.resvRepo.FindOverlapping(sess.RoomID, st, et, sess.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		conflicts = append(conflicts, overlapping...)
+		found, err := s.people.Check(0, class, st, et, sess.ID)
+		var pce *PersonConflictError
+		if errors.As(err, &pce) {
+			personConflicts = append(personConflicts, pce.Conflicts...)
+		} else if err != nil {
+			return nil, nil, err
+		} else {
+			sess.Warnings = append(sess.Warnings, found...)
+		}
+		classID := class.ID
+		resvs = append(resvs, models.Reservation{
+			RoomID:             sess.RoomID,
+			UserID:             class.ProfessorID,
+			ClassID:            &classID,
+			TermID:             class.TermID,
+			StartTime:          st,
+			EndTime:            et,
+			Purpose:            class.Name,
+			EstimatedAttendees: len(class.Students),
+			Status:             "ACTIVE",
+		})
+	}
+	if len(personConflicts) > 0 {
+		return nil, nil, &PersonConflictError{Conflicts: personConflicts}
+	}
+	return resvs, conflicts, nil
+}
+
+// isHoliday indica si el día local de d es feriado del período
+func isHoliday(term *models.Term, d time.Time) bool {
+	return holidayOn(term, localDate(d))
+}
+
+// holidayOn compara day (ver localDate) con las fechas de feriado, que son
+// días calendario sin zona y se toman tal como se guardaron
+func holidayOn(term *models.Term, day time.Time) bool {
+	for _, h := range term.Holidays {
+		if calendarDate(h.Date).Equal(day) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+
+	"programcion-backend/internal/models"
+)
+
+// Roles del equipo docente de una clase
+const (
+	StaffOwner     = "OWNER"
+	StaffCoTeacher = "CO_TEACHER"
+	StaffTA        = "TA"
+)
+
+// Permisos sobre una clase
+const (
+	PermEditClass      = "class.edit"       // modificar datos y cupo
+	PermDeleteClass    = "class.delete"     // eliminar la clase
+	PermManageRoster   = "class.roster"     // inscribir/dar de baja, códigos y solicitudes
+	PermManageSchedule = "class.schedule"   // sesiones semanales
+	PermTakeAttendance = "class.attendance" // tomar y corregir asistencia
+	PermViewReports    = "class.reports"    // reportes de asistencia y padrón
+	PermManageStaff    = "class.staff"      // agregar o quitar docentes
+	PermAnnounce       = "class.announce"   // publicar y borrar avisos
+)
+
+var staffPermissions = map[string]map[string]bool{
+	StaffOwner: {
+		PermEditClass: true, PermDeleteClass: true, PermManageRoster: true, PermManageSchedule: true,
+		PermTakeAttendance: true, PermViewReports: true, PermManageStaff: true, PermAnnounce: true,
+	},
+	StaffCoTeacher: {
+		PermEditClass: true, PermManageRoster: true, PermManageSchedule: true,
+		PermTakeAttendance: true, PermViewReports: true, PermAnnounce: true,
+	},
+	StaffTA: {
+		PermManageRoster: true, PermTakeAttendance: true, PermViewReports: true, PermAnnounce: true,
+	},
+}
+
+// StaffRole devuelve el rol del usuario en la clase ("" si no forma parte del equipo)
+func (s *ClassService) StaffRole(classID, userID uint) (string, error) {
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return "", err
+	}
+	if class.ProfessorID == userID {
+		return StaffOwner, nil
+	}
+	for _, m := range class.Staff {
+		if m.UserID == userID {
+			return m.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// HasPermission indica si el usuario puede realizar la acción sobre la clase
+func (s *ClassService) HasPermission(classID, userID uint, perm string) (bool, error) {
+	role, err := s.StaffRole(classID, userID)
+	if err != nil {
+		return false, err
+	}
+	return staffPermissions[role][perm], nil
+}
+
+func (s *ClassService) GetStaff(classID uint) ([]models.ClassStaff, error) {
+	return s.repo.GetStaff(classID)
+}
+
+// AddStaff agrega un docente a la clase. Los co-docentes deben ser profesores;
+// los ayudantes pueden ser profesores o estudiantes.
+func (s *ClassService) AddStaff(classID, userID uint, role string) (*models.ClassStaff, error) {
+	if role != StaffCoTeacher && role != StaffTA {
+		return nil, errors.New("invalid staff role")
+	}
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+	if class.ArchivedAt != nil {
+		return nil, errClassArchived
+	}
+	if class.ProfessorID == userID {
+		return nil, errors.New("user is already the class owner")
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsConfirmed {
+		return nil, errors.New("user account not confirmed")
+	}
+	if role == StaffCoTeacher && user.Role != "PROFESSOR" {
+		return nil, errors.New("co-teachers must be professors")
+	}
+	if user.Role != "PROFESSOR" && user.Role != "STUDENT" {
+		return nil, errors.New("invalid user for class staff")
+	}
+	m := &models.ClassStaff{ClassID: classID, UserID: userID, Role: role}
+	if err := s.repo.SetStaffMember(m); err != nil {
+		return nil, err
+	}
+	m.User = user
+	return m, nil
+}
+
+func (s *ClassService) RemoveStaff(classID, userID uint) error {
+	if _, err := s.repo.GetStaffMember(classID, userID); err != nil {
+		return errors.New("staff member not found")
+	}
+	return s.repo.RemoveStaffMember(classID, userID)
+}
+
+func (s *ClassService) GetClassesByStaff(userID, termID uint) ([]models.Class, error) {
+	return s.repo.FindByStaffUserID(userID, termID)
+}
+
+// IsMember indica si el usuario es parte del equipo docente o está inscrito en la clase
+func (s *ClassService) IsMember(classID, userID uint) (bool, error) {
+	role, err := s.StaffRole(classID, userID)
+	if err != nil {
+		return false, err
+	}
+	if role != "" {
+		return true, nil
+	}
+	cs, err := s.repo.GetEnrollment(classID, userID)
+	return err == nil && cs.Status == "ENROLLED", nil
+}
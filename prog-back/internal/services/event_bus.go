@@ -0,0 +1,67 @@
+package services
+
+import (
+	"sync"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/pkg/broker"
+)
+
+// EventHandler procesa un evento de dominio. Si devuelve error el relay
+// reintenta el evento, así que debe tolerar recibirlo más de una vez.
+type EventHandler func(e models.OutboxEvent) error
+
+// EventBus reparte los eventos del outbox entre los suscriptores del proceso
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[int]eventSubscription
+	nextID   int
+}
+
+type eventSubscription struct {
+	pattern string
+	handler EventHandler
+}
+
+// DomainEvents es el bus del proceso; el relay del outbox publica en él
+var DomainEvents = NewEventBus()
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: map[int]eventSubscription{}}
+}
+
+// Subscribe registra un handler para los eventos que cumplen el patrón
+// ("reservation.*", "class.>", "*" = todos). Devuelve la función para darse de baja.
+func (b *EventBus) Subscribe(pattern string, h EventHandler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = eventSubscription{pattern: pattern, handler: h}
+	return func() {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}
+}
+
+func (b *EventBus) Name() string { return "in-process" }
+
+// Publish invoca a los suscriptores en orden de registro y devuelve el primer error
+func (b *EventBus) Publish(e *models.OutboxEvent) error {
+	b.mu.RLock()
+	var handlers []EventHandler
+	for id := 0; id < b.nextID; id++ {
+		if s, ok := b.handlers[id]; ok && (s.pattern == "*" || broker.Match(s.pattern, e.Event)) {
+			handlers = append(handlers, s.handler)
+		}
+	}
+	b.mu.RUnlock()
+	var first error
+	for _, h := range handlers {
+		if err := h(*e); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
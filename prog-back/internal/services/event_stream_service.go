@@ -0,0 +1,402 @@
+package services
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// El stream lee el outbox directamente (no el bus del proceso), así cada
+// réplica ve todos los eventos aunque el relay corra en otra. Los ids se
+// asignan al insertar pero las transacciones confirman en cualquier orden: un
+// id salteado puede ser una transacción todavía abierta. Esos huecos se
+// vuelven a consultar durante streamGapTimeout y, si aparecen, se emiten
+// tarde; pasado ese tiempo se dan por descartados (rollback).
+const (
+	streamPollInterval = time.Second
+	streamGapTimeout   = time.Minute
+	streamMaxGaps      = 1000
+	streamPageSize     = 500
+	streamBuffer       = 64
+)
+
+var streamAggregates = []string{"reservation", "room"}
+
+// StreamEvent es un cambio enviado a los clientes. Resume es el id con el que
+// reanudar (Last-Event-ID): todo lo anterior ya se emitió. Puede ser menor que
+// ID mientras haya huecos pendientes, por lo que al reconectar algún evento
+// puede repetirse.
+type StreamEvent struct {
+	ID     uint
+	Resume uint
+	Event  string
+	Data   json.RawMessage
+}
+
+// StreamFilter define qué eventos recibe un cliente. Los estudiantes sólo
+// reciben sus propias reservas y las de sus clases, igual que en /api/me/timetable.
+// Sus clases se actualizan con los cambios de inscripción del outbox.
+type StreamFilter struct {
+	UserID     uint
+	Role       string
+	BuildingID uint
+	RoomID     uint
+	Mine       bool
+	classIDs   map[uint]bool
+}
+
+type streamClient struct {
+	filter *StreamFilter
+	ch     chan StreamEvent
+}
+
+// StreamSubscription entrega primero los eventos perdidos (Replay) y luego los nuevos
+type StreamSubscription struct {
+	Replay []StreamEvent
+	Events <-chan StreamEvent
+	Close  func()
+}
+
+type EventStreamService struct {
+	repo      *repositories.OutboxRepository
+	roomRepo  *repositories.RoomRepository
+	classRepo *repositories.ClassRepository
+
+	mu        sync.Mutex
+	started   bool
+	cursor    uint               // mayor id visto
+	gaps      map[uint]time.Time // ids salteados -> cuándo se detectaron
+	clients   map[*streamClient]bool
+	buildings map[uint]uint // aula -> edificio
+}
+
+func NewEventStreamService() *EventStreamService {
+	return &EventStreamService{
+		repo:      repositories.NewOutboxRepository(),
+		roomRepo:  repositories.NewRoomRepository(),
+		classRepo: repositories.NewClassRepository(),
+		gaps:      map[uint]time.Time{},
+		clients:   map[*streamClient]bool{},
+		buildings: map[uint]uint{},
+	}
+}
+
+// NewFilter arma el filtro del usuario autenticado
+func (s *EventStreamService) NewFilter(userID uint, role string, buildingID, roomID uint, mine bool) (*StreamFilter, error) {
+	f := &StreamFilter{UserID: userID, Role: role, BuildingID: buildingID, RoomID: roomID, Mine: mine}
+	if role == "STUDENT" {
+		classes, err := s.classRepo.FindClassesByStudentID(userID, 0)
+		if err != nil {
+			return nil, err
+		}
+		f.classIDs = make(map[uint]bool, len(classes))
+		for _, c := range classes {
+			f.classIDs[c.ID] = true
+		}
+	}
+	return f, nil
+}
+
+// Subscribe registra al cliente y devuelve los eventos posteriores a
+// lastEventID (0 = ninguno) que el filtro permite
+func (s *EventStreamService) Subscribe(filter *StreamFilter, lastEventID uint) (*StreamSubscription, error) {
+	if err := s.start(); err != nil {
+		return nil, err
+	}
+	client := &streamClient{filter: filter, ch: make(chan StreamEvent, streamBuffer)}
+	s.mu.Lock()
+	s.clients[client] = true
+	upTo := s.cursor
+	s.mu.Unlock()
+
+	sub := &StreamSubscription{Events: client.ch, Close: func() { s.drop(client) }}
+	for after := lastEventID; lastEventID != 0 && after < upTo; {
+		page, err := s.repo.FindAfter(after, upTo, streamAggregates, streamPageSize)
+		if err != nil {
+			sub.Close()
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		for i := range page {
+			r, building, ok := s.resolve(&page[i])
+			if !ok {
+				continue
+			}
+			s.mu.Lock()
+			ev, ok := s.routeLocked(&page[i], r, building, filter)
+			s.mu.Unlock()
+			if ok {
+				sub.Replay = append(sub.Replay, ev)
+			}
+		}
+		after = page[len(page)-1].ID
+	}
+	return sub, nil
+}
+
+func (s *EventStreamService) drop(c *streamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clients[c] {
+		delete(s.clients, c)
+		close(c.ch)
+	}
+}
+
+// start fija el cursor en el último evento y lanza el sondeo del outbox la
+// primera vez que se conecta un cliente
+func (s *EventStreamService) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return nil
+	}
+	last, err := s.repo.LastID()
+	if err != nil {
+		return err
+	}
+	s.cursor = last
+	s.started = true
+	go s.loop()
+	return nil
+}
+
+func (s *EventStreamService) loop() {
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.poll(); err != nil {
+			log.WithError(err).Error("Error leyendo eventos para el stream")
+		}
+	}
+}
+
+// poll emite primero los huecos que ya confirmaron y después los eventos
+// nuevos. Lee todos los agregados para que los ids de otros tipos no queden
+// como huecos.
+func (s *EventStreamService) poll() error {
+	found, err := s.repo.FindByIDs(s.pendingGaps())
+	if err != nil {
+		return err
+	}
+	for i := range found {
+		s.broadcast(&found[i])
+	}
+	for {
+		page, err := s.repo.FindAfter(s.cursorValue(), 0, nil, streamPageSize)
+		if err != nil || len(page) == 0 {
+			return err
+		}
+		for i := range page {
+			s.broadcast(&page[i])
+		}
+		if len(page) < streamPageSize {
+			return nil
+		}
+	}
+}
+
+func (s *EventStreamService) cursorValue() uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursor
+}
+
+// pendingGaps descarta los huecos vencidos y devuelve los que siguen abiertos
+func (s *EventStreamService) pendingGaps() []uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uint, 0, len(s.gaps))
+	for id, since := range s.gaps {
+		if time.Since(since) > streamGapTimeout {
+			delete(s.gaps, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// trackLocked avanza el cursor registrando los ids salteados, o cierra el
+// hueco si el evento llega tarde
+func (s *EventStreamService) trackLocked(id uint) {
+	if id <= s.cursor {
+		delete(s.gaps, id)
+		return
+	}
+	now := time.Now()
+	for g := s.cursor + 1; g < id && len(s.gaps) < streamMaxGaps; g++ {
+		s.gaps[g] = now
+	}
+	s.cursor = id
+}
+
+// resumeLocked es el mayor id hasta el que no queda nada pendiente
+func (s *EventStreamService) resumeLocked() uint {
+	resume := s.cursor
+	for g := range s.gaps {
+		if g <= resume {
+			resume = g - 1
+		}
+	}
+	return resume
+}
+
+// broadcast registra el evento y lo entrega a los clientes que lo permiten.
+// Un cliente con el buffer lleno se desconecta: al reconectar con
+// Last-Event-ID recupera lo que se perdió.
+func (s *EventStreamService) broadcast(e *models.OutboxEvent) {
+	r, building, routable := s.resolve(e)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trackLocked(e.ID)
+	switch {
+	case e.AggregateType == "room" && routable:
+		s.buildings[r.ID] = r.BuildingID
+	case e.Event == models.EventEnrollmentChanged:
+		s.applyEnrollmentLocked(e)
+	}
+	if !routable {
+		return
+	}
+	for c := range s.clients {
+		ev, ok := s.routeLocked(e, r, building, c.filter)
+		if !ok {
+			continue
+		}
+		select {
+		case c.ch <- ev:
+		default:
+			delete(s.clients, c)
+			close(c.ch)
+		}
+	}
+}
+
+// applyEnrollmentLocked mantiene al día las clases de los estudiantes conectados
+func (s *EventStreamService) applyEnrollmentLocked(e *models.OutboxEvent) {
+	var change repositories.EnrollmentChange
+	if err := json.Unmarshal(e.Payload, &change); err != nil {
+		return
+	}
+	for c := range s.clients {
+		f := c.filter
+		if f.Role != "STUDENT" || f.UserID != change.StudentID {
+			continue
+		}
+		if change.Status == "ENROLLED" {
+			f.classIDs[change.ClassID] = true
+		} else {
+			delete(f.classIDs, change.ClassID)
+		}
+	}
+}
+
+// streamRouting son los campos del payload que deciden quién recibe el evento
+// y qué parte ve
+type streamRouting struct {
+	ID         uint      `json:"id"`
+	RoomID     uint      `json:"room_id"`
+	BuildingID uint      `json:"building_id"`
+	UserID     uint      `json:"user_id"`
+	ClassID    *uint     `json:"class_id"`
+	Private    bool      `json:"private"`
+	Status     string    `json:"status"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// streamPrivateReservation es lo que ve de una reserva privada quien no es su
+// titular ni admin, igual que en la vista pública
+type streamPrivateReservation struct {
+	ID        uint      `json:"id"`
+	RoomID    uint      `json:"room_id"`
+	Purpose   string    `json:"purpose"`
+	Status    string    `json:"status"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Private   bool      `json:"private"`
+}
+
+// resolve decodifica el payload y, para las reservas, busca el edificio del
+// aula. Corre sin el mutex porque puede consultar la base.
+func (s *EventStreamService) resolve(e *models.OutboxEvent) (*streamRouting, uint, bool) {
+	if e.AggregateType != "reservation" && e.AggregateType != "room" {
+		return nil, 0, false
+	}
+	var r streamRouting
+	if err := json.Unmarshal(e.Payload, &r); err != nil {
+		return nil, 0, false
+	}
+	if e.AggregateType == "room" {
+		return &r, r.BuildingID, true
+	}
+	return &r, s.buildingOf(r.RoomID), true
+}
+
+func (s *EventStreamService) routeLocked(e *models.OutboxEvent, r *streamRouting, building uint, f *StreamFilter) (StreamEvent, bool) {
+	ev := StreamEvent{ID: e.ID, Resume: s.resumeLocked(), Event: e.Event, Data: e.Payload}
+	if ev.Resume > ev.ID {
+		ev.Resume = ev.ID
+	}
+	if e.AggregateType == "room" {
+		if f.Mine {
+			return ev, false
+		}
+		return ev, (f.RoomID == 0 || f.RoomID == r.ID) && (f.BuildingID == 0 || f.BuildingID == r.BuildingID)
+	}
+
+	if f.Mine && r.UserID != f.UserID {
+		return ev, false
+	}
+	if f.Role == "STUDENT" && r.UserID != f.UserID && (r.ClassID == nil || !f.classIDs[*r.ClassID]) {
+		return ev, false
+	}
+	if f.RoomID != 0 && f.RoomID != r.RoomID {
+		return ev, false
+	}
+	if f.BuildingID != 0 && f.BuildingID != building {
+		return ev, false
+	}
+	if r.Private && f.Role != "ADMIN" && r.UserID != f.UserID {
+		data, err := json.Marshal(streamPrivateReservation{
+			ID: r.ID, RoomID: r.RoomID, Purpose: privateReservationTitle, Status: r.Status,
+			StartTime: r.StartTime, EndTime: r.EndTime, Private: true,
+		})
+		if err != nil {
+			return ev, false
+		}
+		ev.Data = data
+	}
+	return ev, true
+}
+
+// buildingOf resuelve el edificio del aula con una caché que se actualiza
+// con los eventos room.updated en vivo. La consulta a la base se hace fuera
+// del mutex.
+func (s *EventStreamService) buildingOf(roomID uint) uint {
+	s.mu.Lock()
+	b, ok := s.buildings[roomID]
+	s.mu.Unlock()
+	if ok {
+		return b
+	}
+	room, err := s.roomRepo.GetByID(roomID)
+	if err != nil {
+		return 0
+	}
+	s.mu.Lock()
+	if _, ok := s.buildings[roomID]; !ok {
+		s.buildings[roomID] = room.BuildingID
+	}
+	s.mu.Unlock()
+	return room.BuildingID
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+)
+
+type ExamService struct {
+	repo      *repositories.ExamRepository
+	classRepo *repositories.ClassRepository
+	roomRepo  *repositories.RoomRepository
+	resvRepo  *repositories.ReservationRepository
+	userRepo  *repositories.UserRepository
+	termRepo  *repositories.TermRepository
+}
+
+func NewExamService() *ExamService {
+	return &ExamService{
+		repo:      repositories.NewExamRepository(),
+		classRepo: repositories.NewClassRepository(),
+		roomRepo:  repositories.NewRoomRepository(),
+		resvRepo:  repositories.NewReservationRepository(),
+		userRepo:  repositories.NewUserRepository(),
+		termRepo:  repositories.NewTermRepository(),
+	}
+}
+
+// ExamRequest describe un examen: horario, aulas y vigilantes de cada aula
+type ExamRequest struct {
+	Title     string            `json:"title" binding:"required"`
+	StartTime time.Time         `json:"start_time" binding:"required"`
+	EndTime   time.Time         `json:"end_time" binding:"required"`
+	Rooms     []ExamRoomRequest `json:"rooms" binding:"required,min=1,dive"`
+}
+
+type ExamRoomRequest struct {
+	RoomID         uint   `json:"room_id" binding:"required"`
+	InvigilatorIDs []uint `json:"invigilator_ids" binding:"required,min=1"`
+}
+
+// examCapacity devuelve las plazas del aula en modo examen
+func examCapacity(room *models.Room) int {
+	if room.ExamCapacity > 0 {
+		return room.ExamCapacity
+	}
+	return room.Capacity / 2
+}
+
+// Schedule reserva las aulas del examen, reparte a los inscritos entre ellas
+// en orden alfabético y asigna los vigilantes
+func (s *ExamService) Schedule(classID, createdByID uint, req ExamRequest) (*models.Exam, error) {
+	if !req.EndTime.After(req.StartTime) {
+		return nil, errors.New("end_time must be after start_time")
+	}
+	class, err := s.classRepo.FindByID(classID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+	if class.ArchivedAt != nil {
+		return nil, errClassArchived
+	}
+	if class.TermID != nil {
+		term, err := s.termRepo.GetByID(*class.TermID)
+		if err != nil {
+			return nil, errors.New("term not found")
+		}
+		if err := checkTermBounds(term, req.StartTime, req.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	students := append([]models.User(nil), class.Students...)
+	sort.Slice(students, func(i, j int) bool {
+		if students[i].Name != students[j].Name {
+			return students[i].Name < students[j].Name
+		}
+		return students[i].ID < students[j].ID
+	})
+	enrolled := make(map[uint]bool, len(students))
+	for _, st := range students {
+		enrolled[st.ID] = true
+	}
+
+	rooms := make([]*models.Room, len(req.Rooms))
+	caps := make([]int, len(req.Rooms))
+	seenRoom := map[uint]bool{}
+	seenInvigilator := map[uint]bool{}
+	total := 0
+	for i, rr := range req.Rooms {
+		if seenRoom[rr.RoomID] {
+			return nil, fmt.Errorf("room %d listed twice", rr.RoomID)
+		}
+		seenRoom[rr.RoomID] = true
+		room, err := s.roomRepo.GetByID(rr.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("room %d not found", rr.RoomID)
+		}
+		overlaps, err := s.resvRepo.HasOverlapping(room.ID, req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		if overlaps {
+			return nil, fmt.Errorf("room %s not available (overlap)", room.Name)
+		}
+		for _, uid := range rr.InvigilatorIDs {
+			if err := s.checkInvigilator(uid, enrolled, seenInvigilator, req.StartTime, req.EndTime); err != nil {
+				return nil, err
+			}
+		}
+		rooms[i] = room
+		caps[i] = examCapacity(room)
+		total += caps[i]
+	}
+	if total < len(students) {
+		return nil, fmt.Errorf("not enough exam seats: %d students, %d seats", len(students), total)
+	}
+
+	counts := splitSeats(len(students), caps)
+	examRooms := make([]models.ExamRoom, len(rooms))
+	resvs := make([]models.Reservation, len(rooms))
+	next := 0
+	for i, room := range rooms {
+		er := models.ExamRoom{RoomID: room.ID}
+		for _, uid := range req.Rooms[i].InvigilatorIDs {
+			er.Invigilators = append(er.Invigilators, models.ExamInvigilator{UserID: uid})
+		}
+		for seat := 1; seat <= counts[i]; seat++ {
+			er.Seats = append(er.Seats, models.ExamSeat{StudentID: students[next].ID, SeatNumber: seat})
+			next++
+		}
+		examRooms[i] = er
+		resvs[i] = models.Reservation{
+			RoomID:             room.ID,
+			UserID:             createdByID,
+			ClassID:            &class.ID,
+			TermID:             class.TermID,
+			Kind:               "EXAM",
+			StartTime:          req.StartTime,
+			EndTime:            req.EndTime,
+			Purpose:            fmt.Sprintf("Examen: %s - %s", class.Name, req.Title),
+			EstimatedAttendees: counts[i],
+			Status:             "ACTIVE",
+		}
+	}
+
+	exam := &models.Exam{
+		ClassID:     class.ID,
+		Title:       req.Title,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Status:      "ACTIVE",
+		CreatedByID: &createdByID,
+	}
+	if err := s.repo.CreateWithReservations(exam, examRooms, resvs); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(exam.ID)
+}
+
+// checkInvigilator valida que el vigilante exista, no rinda el examen, no
+// esté asignado a dos aulas ni tenga otro compromiso en el horario
+func (s *ExamService) checkInvigilator(userID uint, enrolled, seen map[uint]bool, start, end time.Time) error {
+	if seen[userID] {
+		return fmt.Errorf("invigilator %d assigned to more than one room", userID)
+	}
+	seen[userID] = true
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("invigilator %d not found", userID)
+	}
+	if !user.IsConfirmed {
+		return fmt.Errorf("invigilator %d not confirmed", userID)
+	}
+	if enrolled[userID] {
+		return fmt.Errorf("invigilator %d is enrolled in the class", userID)
+	}
+	busy, err := s.repo.InvigilatorBusy(userID, start, end)
+	if err != nil {
+		return err
+	}
+	if busy {
+		return fmt.Errorf("invigilator %s is already booked at that time", user.Name)
+	}
+	return nil
+}
+
+// splitSeats reparte n estudiantes entre aulas de forma proporcional a su
+// capacidad de examen; el resto se asigna en orden a las aulas con lugar
+func splitSeats(n int, caps []int) []int {
+	total := 0
+	for _, c := range caps {
+		total += c
+	}
+	counts := make([]int, len(caps))
+	if total == 0 {
+		return counts
+	}
+	assigned := 0
+	for i, c := range caps {
+		counts[i] = n * c / total
+		assigned += counts[i]
+	}
+	for i := 0; assigned < n; i = (i + 1) % len(caps) {
+		if counts[i] < caps[i] {
+			counts[i]++
+			assigned++
+		}
+	}
+	return counts
+}
+
+func (s *ExamService) Get(id uint) (*models.Exam, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *ExamService) ListByClass(classID uint) ([]models.Exam, error) {
+	return s.repo.FindByClassID(classID)
+}
+
+func (s *ExamService) Cancel(id uint) error {
+	exam, err := s.repo.FindByID(id)
+	if err != nil {
+		return errors.New("exam not found")
+	}
+	if exam.Status != "ACTIVE" {
+		return errors.New("exam is not active")
+	}
+	return s.repo.Cancel(id)
+}
+
+// SeatingList devuelve los asientos de un aula del examen
+func (s *ExamService) SeatingList(examID, roomID uint) (*models.ExamRoom, error) {
+	exam, err := s.repo.FindByID(examID)
+	if err != nil {
+		return nil, errors.New("exam not found")
+	}
+	for i := range exam.Rooms {
+		if exam.Rooms[i].RoomID == roomID {
+			return &exam.Rooms[i], nil
+		}
+	}
+	return nil, errors.New("room not part of this exam")
+}
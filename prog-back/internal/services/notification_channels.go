@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+)
+
+// Canales de entrega
+const (
+	ChannelEmail = "EMAIL"
+	ChannelInApp = "IN_APP"
+)
+
+// NotificationChannel entrega una notificación ya renderizada a un usuario
+type NotificationChannel interface {
+	Send(user *models.User, d *models.NotificationDelivery) error
+}
+
+// inAppChannel guarda la notificación en la bandeja de entrada del usuario
+type inAppChannel struct {
+	repo *repositories.NotificationRepository
+}
+
+func (ch *inAppChannel) Send(user *models.User, d *models.NotificationDelivery) error {
+	return ch.repo.CreateNotification(&models.Notification{
+		UserID:    user.ID,
+		Event:     d.Event,
+		Title:     d.Subject,
+		Body:      d.Body,
+		CreatedAt: time.Now(),
+	})
+}
+
+// smtpChannel envía la notificación por correo
+type smtpChannel struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// newSMTPChannel configura el correo saliente. Devuelve nil si no hay
+// servidor configurado.
+func newSMTPChannel(cfg config.SMTPConfig) *smtpChannel {
+	if cfg.Host == "" {
+		return nil
+	}
+	from := cfg.From
+	if from == "" {
+		from = cfg.User
+	}
+	ch := &smtpChannel{addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)), from: from}
+	if cfg.User != "" {
+		ch.auth = smtp.PlainAuth("", cfg.User, cfg.Password, cfg.Host)
+	}
+	return ch
+}
+
+func (ch *smtpChannel) Send(user *models.User, d *models.NotificationDelivery) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", ch.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", user.Email)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", d.Subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(d.Body)
+	msg.WriteString("\r\n")
+	return smtp.SendMail(ch.addr, ch.auth, ch.from, []string{user.Email}, []byte(msg.String()))
+}
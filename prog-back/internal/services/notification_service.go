@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Reintentos de entrega: espera de 30s, 1m, 2m, 4m... hasta maxDeliveryAttempts
+const (
+	maxDeliveryAttempts = 5
+	deliveryBaseBackoff = 30 * time.Second
+	deliveryLease       = 2 * time.Minute
+	deliveryBatchSize   = 50
+)
+
+// NotificationService implementa el circuito evento → plantilla → canal. Notify
+// encola un envío por canal y el despachador (jobs) los entrega en segundo plano.
+type NotificationService struct {
+	repo     *repositories.NotificationRepository
+	userRepo *repositories.UserRepository
+	channels map[string]NotificationChannel
+}
+
+func NewNotificationService(cfg *config.Config) *NotificationService {
+	repo := repositories.NewNotificationRepository()
+	channels := map[string]NotificationChannel{
+		ChannelInApp: &inAppChannel{repo: repo},
+	}
+	if smtpCh := newSMTPChannel(cfg.SMTP); smtpCh != nil {
+		channels[ChannelEmail] = smtpCh
+	}
+	return &NotificationService{
+		repo:     repo,
+		userRepo: repositories.NewUserRepository(),
+		channels: channels,
+	}
+}
+
+// EmailEnabled indica si hay un canal de correo configurado (SMTP_HOST)
+func (s *NotificationService) EmailEnabled() bool {
+	_, ok := s.channels[ChannelEmail]
+	return ok
+}
+
+// Notify encola el evento para los usuarios según sus preferencias. Los
+// errores sólo se registran para no hacer fallar la operación que lo originó.
+func (s *NotificationService) Notify(event string, userIDs []uint, data map[string]string) {
+	if err := s.enqueue(event, userIDs, data); err != nil {
+		log.WithError(err).WithField("event", event).Error("Error encolando notificaciones")
+	}
+}
+
+func (s *NotificationService) enqueue(event string, userIDs []uint, data map[string]string) error {
+	tmpls, ok := notificationTemplates[event]
+	if !ok {
+		return errors.New("unknown notification event")
+	}
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	prefs, err := s.repo.PreferencesForEvent(userIDs, event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []models.NotificationDelivery
+	for _, u := range users {
+		tmpl, ok := tmpls[u.Locale]
+		if !ok {
+			tmpl = tmpls[defaultLocale]
+		}
+		subject, err := renderTemplate(tmpl.Subject, data)
+		if err != nil {
+			return err
+		}
+		body, err := renderTemplate(tmpl.Body, data)
+		if err != nil {
+			return err
+		}
+		pref, ok := prefs[u.ID]
+		if !ok {
+			pref = models.NotificationPreference{Email: true, InApp: true}
+		}
+		if emailOnlyEvents[event] {
+			pref = models.NotificationPreference{Email: true}
+		}
+		for channel, enabled := range map[string]bool{ChannelEmail: pref.Email, ChannelInApp: pref.InApp} {
+			if _, configured := s.channels[channel]; !enabled || !configured {
+				continue
+			}
+			deliveries = append(deliveries, models.NotificationDelivery{
+				UserID:        u.ID,
+				Event:         event,
+				Channel:       channel,
+				Subject:       subject,
+				Body:          body,
+				Status:        "PENDING",
+				NextAttemptAt: now,
+			})
+		}
+	}
+	return s.repo.CreateDeliveries(deliveries)
+}
+
+func renderTemplate(text string, data map[string]string) (string, error) {
+	t, err := template.New("n").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// DispatchDue entrega los envíos pendientes y devuelve cuántos salieron bien
+func (s *NotificationService) DispatchDue() (int, error) {
+	list, err := s.repo.ClaimDueDeliveries(deliveryBatchSize, deliveryLease)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range list {
+		d := &list[i]
+		if err := s.deliver(d); err != nil {
+			s.recordFailure(d, err)
+			continue
+		}
+		if err := s.repo.MarkDeliverySent(d.ID, time.Now()); err != nil {
+			log.WithError(err).WithField("delivery_id", d.ID).Error("Error marcando notificación como enviada")
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *NotificationService) deliver(d *models.NotificationDelivery) error {
+	ch, ok := s.channels[d.Channel]
+	if !ok {
+		return errors.New("channel not configured: " + d.Channel)
+	}
+	user, err := s.userRepo.GetByID(d.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	return ch.Send(user, d)
+}
+
+func (s *NotificationService) recordFailure(d *models.NotificationDelivery, sendErr error) {
+	attempts := d.Attempts + 1
+	var next *time.Time
+	if attempts < maxDeliveryAttempts {
+		t := time.Now().Add(deliveryBaseBackoff << (attempts - 1))
+		next = &t
+	}
+	log.WithError(sendErr).WithFields(log.Fields{"delivery_id": d.ID, "attempt": attempts}).
+		Warn("Error entregando notificación")
+	if err := s.repo.MarkDeliveryFailed(d.ID, attempts, next, sendErr.Error()); err != nil {
+		log.WithError(err).WithField("delivery_id", d.ID).Error("Error registrando fallo de notificación")
+	}
+}
+
+// Bandeja de entrada in-app
+
+const inboxLimit = 100
+
+func (s *NotificationService) Inbox(userID uint, unreadOnly bool) ([]models.Notification, int64, error) {
+	list, err := s.repo.ListForUser(userID, unreadOnly, inboxLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.repo.UnreadCount(userID)
+	return list, unread, err
+}
+
+func (s *NotificationService) MarkRead(id, userID uint) error {
+	found, err := s.repo.MarkRead(id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(userID uint) error {
+	return s.repo.MarkAllRead(userID, time.Now())
+}
+
+// Preferencias
+
+// NotificationSettings son el idioma y las preferencias por evento del usuario
+type NotificationSettings struct {
+	Locale      string                          `json:"locale"`
+	Preferences []models.NotificationPreference `json:"preferences"`
+}
+
+// GetSettings devuelve las preferencias de todos los eventos, completando
+// con ambos canales activos los que el usuario no configuró
+func (s *NotificationService) GetSettings(userID uint) (*NotificationSettings, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	saved, err := s.repo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	byEvent := make(map[string]models.NotificationPreference, len(saved))
+	for _, p := range saved {
+		byEvent[p.Event] = p
+	}
+	out := &NotificationSettings{Locale: user.Locale}
+	for _, ev := range NotificationEvents {
+		p, ok := byEvent[ev]
+		if !ok {
+			p = models.NotificationPreference{UserID: userID, Event: ev, Email: true, InApp: true}
+		}
+		out.Preferences = append(out.Preferences, p)
+	}
+	return out, nil
+}
+
+func (s *NotificationService) UpdateSettings(userID uint, settings NotificationSettings) (*NotificationSettings, error) {
+	if settings.Locale != "" {
+		if _, ok := notificationTemplates[EventAccountConfirmed][settings.Locale]; !ok {
+			return nil, errors.New("unsupported locale (use es or en)")
+		}
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		user.Locale = settings.Locale
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	for i := range settings.Preferences {
+		if _, ok := notificationTemplates[settings.Preferences[i].Event]; !ok {
+			return nil, errors.New("unknown event: " + settings.Preferences[i].Event)
+		}
+		settings.Preferences[i].UserID = userID
+	}
+	if err := s.repo.SavePreferences(settings.Preferences); err != nil {
+		return nil, err
+	}
+	return s.GetSettings(userID)
+}
@@ -0,0 +1,138 @@
+package services
+
+// Eventos que generan notificaciones
+const (
+	EventAccountConfirmed      = "account.confirmed"
+	EventReservationCancelled  = "reservation.cancelled"
+	EventClassEnrolled         = "class.enrolled"
+	EventClassWaitlisted       = "class.waitlisted"
+	EventClassWaitlistPromoted = "class.waitlist_promoted"
+	EventClassAnnouncement     = "class.announcement"
+	EventReservationReminder   = "reservation.reminder"
+	EventAgendaDigest          = "agenda.digest"
+	EventAccountInvited        = "account.invited"
+)
+
+// Eventos que sólo salen por correo, sin preferencias: llevan datos que no
+// deben quedar en la bandeja (p. ej. el token de invitación)
+var emailOnlyEvents = map[string]bool{
+	EventAccountInvited: true,
+}
+
+// NotificationEvents es la lista de eventos que el usuario puede configurar
+var NotificationEvents = []string{
+	EventAccountConfirmed,
+	EventReservationCancelled,
+	EventClassEnrolled,
+	EventClassWaitlisted,
+	EventClassWaitlistPromoted,
+	EventClassAnnouncement,
+	EventReservationReminder,
+	EventAgendaDigest,
+}
+
+const defaultLocale = "es"
+
+type notificationTemplate struct {
+	Subject string
+	Body    string
+}
+
+// notificationTemplates[evento][idioma] son plantillas text/template que
+// reciben los datos del evento
+var notificationTemplates = map[string]map[string]notificationTemplate{
+	EventAccountConfirmed: {
+		"es": {
+			Subject: "Tu cuenta fue confirmada",
+			Body:    "Hola {{.Name}}, un administrador confirmó tu cuenta. Ya podés iniciar sesión.",
+		},
+		"en": {
+			Subject: "Your account has been confirmed",
+			Body:    "Hi {{.Name}}, an administrator confirmed your account. You can now sign in.",
+		},
+	},
+	EventReservationCancelled: {
+		"es": {
+			Subject: "Reserva cancelada: {{.Purpose}}",
+			Body:    "Un administrador canceló tu reserva \"{{.Purpose}}\" en {{.Room}} del {{.Start}}.",
+		},
+		"en": {
+			Subject: "Reservation cancelled: {{.Purpose}}",
+			Body:    "An administrator cancelled your reservation \"{{.Purpose}}\" in {{.Room}} on {{.Start}}.",
+		},
+	},
+	EventClassEnrolled: {
+		"es": {
+			Subject: "Inscripción confirmada en {{.Class}}",
+			Body:    "Quedaste inscrito en la clase {{.Class}}.",
+		},
+		"en": {
+			Subject: "Enrolled in {{.Class}}",
+			Body:    "You are now enrolled in {{.Class}}.",
+		},
+	},
+	EventClassWaitlisted: {
+		"es": {
+			Subject: "Lista de espera de {{.Class}}",
+			Body:    "La clase {{.Class}} no tiene cupo. Quedaste en la lista de espera y te avisaremos si se libera un lugar.",
+		},
+		"en": {
+			Subject: "Waitlisted for {{.Class}}",
+			Body:    "{{.Class}} is full. You are on the waitlist and will be notified if a seat opens up.",
+		},
+	},
+	EventClassWaitlistPromoted: {
+		"es": {
+			Subject: "Se liberó un lugar en {{.Class}}",
+			Body:    "Se liberó un cupo y ahora estás inscrito en la clase {{.Class}}.",
+		},
+		"en": {
+			Subject: "A seat opened up in {{.Class}}",
+			Body:    "A seat opened up and you are now enrolled in {{.Class}}.",
+		},
+	},
+	EventClassAnnouncement: {
+		"es": {
+			Subject: "[{{.Class}}] {{.Title}}",
+			Body:    "{{.Body}}",
+		},
+		"en": {
+			Subject: "[{{.Class}}] {{.Title}}",
+			Body:    "{{.Body}}",
+		},
+	},
+	EventReservationReminder: {
+		"es": {
+			Subject: "Recordatorio: {{.Purpose}} el {{.Start}}",
+			Body:    "Tenés una reserva \"{{.Purpose}}\" en {{.Room}} el {{.Start}}. Recordá hacer check-in al llegar.",
+		},
+		"en": {
+			Subject: "Reminder: {{.Purpose}} on {{.Start}}",
+			Body:    "You have a reservation \"{{.Purpose}}\" in {{.Room}} on {{.Start}}. Remember to check in when you arrive.",
+		},
+	},
+	EventAccountInvited: {
+		"es": {
+			Subject: "Te invitaron a {{.Class}}",
+			Body: "Hola {{.Name}}, te agregaron a la clase {{.Class}} y creamos una cuenta para {{.Email}}.\n" +
+				"Para elegir tu contraseña usá este código de invitación (vence el {{.Expires}}):\n\n{{.Token}}\n\n" +
+				"Un administrador debe confirmar la cuenta antes de que puedas iniciar sesión.",
+		},
+		"en": {
+			Subject: "You have been invited to {{.Class}}",
+			Body: "Hi {{.Name}}, you were added to {{.Class}} and an account was created for {{.Email}}.\n" +
+				"Use this invitation code to choose your password (expires on {{.Expires}}):\n\n{{.Token}}\n\n" +
+				"An administrator must confirm the account before you can sign in.",
+		},
+	},
+	EventAgendaDigest: {
+		"es": {
+			Subject: "Tu agenda del {{.Date}} ({{.Count}} reservas)",
+			Body:    "Reservas de hoy:\n{{.Items}}",
+		},
+		"en": {
+			Subject: "Your agenda for {{.Date}} ({{.Count}} reservations)",
+			Body:    "Today's reservations:\n{{.Items}}",
+		},
+	},
+}
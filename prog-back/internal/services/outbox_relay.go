@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/broker"
+	"programcion-backend/pkg/config"
+)
+
+// EventSink es un destino de los eventos del outbox. Publish debe ser
+// idempotente: ante un fallo el relay vuelve a entregar el evento a todos los sinks.
+type EventSink interface {
+	Name() string
+	Publish(e *models.OutboxEvent) error
+}
+
+// Reintentos del relay: 5s, 10s, 20s... con tope de 10 minutos, sin límite de intentos
+const (
+	outboxBatchSize   = 100
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+	outboxTimeout     = 10 * time.Second
+)
+
+// LocalBroker es el broker en memoria del proceso. Un adaptador de NATS o
+// Kafka que implemente broker.Broker puede reemplazarlo en NewBrokerSink.
+var LocalBroker broker.Broker = broker.NewMemoryBroker()
+
+type OutboxRelay struct {
+	repo  *repositories.OutboxRepository
+	sinks []EventSink
+}
+
+func NewOutboxRelay(sinks ...EventSink) *OutboxRelay {
+	return &OutboxRelay{repo: repositories.NewOutboxRepository(), sinks: sinks}
+}
+
+// RelayPending publica los eventos pendientes y devuelve cuántos se publicaron
+func (r *OutboxRelay) RelayPending() (int, error) {
+	return r.repo.ProcessPending(outboxBatchSize, outboxBackoff, r.publish)
+}
+
+func (r *OutboxRelay) publish(e *models.OutboxEvent) error {
+	for _, sink := range r.sinks {
+		if err := sink.Publish(e); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Prune borra los eventos ya publicados hace más de retention
+func (r *OutboxRelay) Prune(retention time.Duration) (int64, error) {
+	return r.repo.PrunePublished(time.Now().Add(-retention))
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	if attempts > 7 {
+		return outboxMaxBackoff
+	}
+	if d := outboxBaseBackoff << attempts; d < outboxMaxBackoff {
+		return d
+	}
+	return outboxMaxBackoff
+}
+
+// webhookSink encola entregas de webhook para cada evento
+type webhookSink struct {
+	svc *WebhookService
+}
+
+func NewWebhookSink(cfg *config.Config) EventSink {
+	return &webhookSink{svc: NewWebhookService(cfg)}
+}
+
+func (s *webhookSink) Name() string { return "webhooks" }
+
+func (s *webhookSink) Publish(e *models.OutboxEvent) error { return s.svc.Publish(e) }
+
+// brokerSink publica cada evento en el subject con su nombre; la clave es el
+// agregado, así un broker particionado conserva el orden por agregado
+type brokerSink struct {
+	b broker.Broker
+}
+
+func NewBrokerSink(b broker.Broker) EventSink { return &brokerSink{b: b} }
+
+func (s *brokerSink) Name() string { return "broker" }
+
+// brokerEnvelope es el cuerpo del mensaje publicado en el broker
+type brokerEnvelope struct {
+	ID            uint            `json:"id"`
+	Event         string          `json:"event"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func (s *brokerSink) Publish(e *models.OutboxEvent) error {
+	body, err := json.Marshal(brokerEnvelope{
+		ID:            e.ID,
+		Event:         e.Event,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		CreatedAt:     e.CreatedAt,
+		Data:          e.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), outboxTimeout)
+	defer cancel()
+	return s.b.Publish(ctx, broker.Message{
+		Subject: e.Event,
+		Key:     fmt.Sprintf("%s:%d", e.AggregateType, e.AggregateID),
+		Data:    body,
+		Headers: map[string]string{"event-id": strconv.FormatUint(uint64(e.ID), 10)},
+	})
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+)
+
+// Modos de PERSON_CONFLICT_MODE
+const (
+	ConflictModeError = config.ConflictModeError
+	ConflictModeWarn  = config.ConflictModeWarn
+	ConflictModeOff   = config.ConflictModeOff
+)
+
+// PersonConflictError se devuelve en modo error cuando alguna persona
+// involucrada ya está ocupada en ese horario
+type PersonConflictError struct {
+	Conflicts []models.ReservationConflict
+}
+
+func (e *PersonConflictError) Error() string {
+	names := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		names = append(names, fmt.Sprintf("#%d %q", c.Reservation.ID, c.Reservation.Purpose))
+	}
+	return "person already booked at that time: " + strings.Join(names, ", ")
+}
+
+// personConflictChecker verifica que el usuario que reserva, los docentes de
+// la clase y opcionalmente sus estudiantes no estén ocupados en otra reserva
+type personConflictChecker struct {
+	resvRepo      *repositories.ReservationRepository
+	classRepo     *repositories.ClassRepository
+	mode          string
+	checkStudents bool
+}
+
+func newPersonConflictChecker(cfg config.ReservationsConfig) *personConflictChecker {
+	return &personConflictChecker{
+		resvRepo:      repositories.NewReservationRepository(),
+		classRepo:     repositories.NewClassRepository(),
+		mode:          cfg.PersonConflictMode,
+		checkStudents: cfg.PersonConflictStudents,
+	}
+}
+
+// Check busca choques para la reserva. En modo error devuelve un
+// *PersonConflictError; en modo warn devuelve los choques sin error.
+func (p *personConflictChecker) Check(userID uint, class *models.Class, start, end time.Time, excludeSessionID uint) ([]models.ReservationConflict, error) {
+	if p.mode == ConflictModeOff {
+		return nil, nil
+	}
+	reasons := map[uint]string{}
+	if userID != 0 {
+		reasons[userID] = "USER"
+	}
+	if class != nil {
+		reasons[class.ProfessorID] = "PROFESSOR"
+		for _, st := range class.Staff {
+			if st.Role == StaffOwner || st.Role == StaffCoTeacher {
+				reasons[st.UserID] = "PROFESSOR"
+			}
+		}
+	}
+	ids := make([]uint, 0, len(reasons))
+	for id := range reasons {
+		ids = append(ids, id)
+	}
+	found, err := p.resvRepo.FindPersonOverlaps(ids, start, end, excludeSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.ReservationConflict
+	seen := map[uint]bool{}
+	for _, r := range found {
+		who := personInvolved(r, reasons)
+		seen[r.ID] = true
+		conflicts = append(conflicts, models.ReservationConflict{Reason: reasons[who], UserID: who, Reservation: r})
+	}
+	if p.checkStudents && class != nil {
+		shared, err := p.resvRepo.FindStudentOverlaps(class.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range shared {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				conflicts = append(conflicts, models.ReservationConflict{Reason: "STUDENT", Reservation: r})
+			}
+		}
+	}
+	if len(conflicts) > 0 && p.mode == ConflictModeError {
+		return nil, &PersonConflictError{Conflicts: conflicts}
+	}
+	return conflicts, nil
+}
+
+// personInvolved identifica cuál de las personas verificadas ocupa la reserva
+func personInvolved(r models.Reservation, reasons map[uint]string) uint {
+	if _, ok := reasons[r.UserID]; ok {
+		return r.UserID
+	}
+	if r.Class != nil {
+		if _, ok := reasons[r.Class.ProfessorID]; ok {
+			return r.Class.ProfessorID
+		}
+		for _, st := range r.Class.Staff {
+			if _, ok := reasons[st.UserID]; ok {
+				return st.UserID
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+)
+
+// Título que reemplaza al motivo de las reservas privadas en vistas públicas
+const privateReservationTitle = "Reservado"
+
+const (
+	publicDefaultRange = 7 * 24 * time.Hour
+	publicMaxRange     = 31 * 24 * time.Hour
+	publicDefaultLimit = 100
+	publicMaxLimit     = 500
+)
+
+var ErrInvalidPublicQuery = errors.New("invalid query")
+
+// PublicReservation es la proyección pública de una reserva. Los campos
+// opcionales se omiten si no están habilitados o si la reserva es privada.
+type PublicReservation struct {
+	ID                 uint      `json:"id"`
+	RoomID             uint      `json:"room_id"`
+	RoomName           string    `json:"room_name"`
+	BuildingID         uint      `json:"building_id"`
+	BuildingName       string    `json:"building_name"`
+	StartTime          time.Time `json:"start_time"`
+	EndTime            time.Time `json:"end_time"`
+	Private            bool      `json:"private"`
+	Purpose            *string   `json:"purpose,omitempty"`
+	ClassName          *string   `json:"class_name,omitempty"`
+	EstimatedAttendees *int      `json:"estimated_attendees,omitempty"`
+	UserName           *string   `json:"user_name,omitempty"`
+	UserEmail          *string   `json:"user_email,omitempty"`
+}
+
+type PublicReservationPage struct {
+	From       time.Time           `json:"from"`
+	To         time.Time           `json:"to"`
+	Items      []PublicReservation `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// PublicReservationRequest son los filtros de la consulta. From/To nulos
+// equivalen a los próximos 7 días; Fields vacío usa todos los habilitados.
+type PublicReservationRequest struct {
+	From       *time.Time
+	To         *time.Time
+	BuildingID uint
+	RoomID     uint
+	Cursor     string
+	Limit      int
+	Fields     []string
+}
+
+type PublicReservationService struct {
+	repo   *repositories.ReservationRepository
+	fields map[string]bool
+}
+
+// NewPublicReservationService muestra sólo los campos opcionales habilitados
+// en cfg.PublicListing.ReservationFields (ver config.PublicReservationFields)
+func NewPublicReservationService(cfg *config.Config) *PublicReservationService {
+	fields := map[string]bool{}
+	for _, f := range cfg.PublicListing.ReservationFields {
+		fields[f] = true
+	}
+	return &PublicReservationService{repo: repositories.NewReservationRepository(), fields: fields}
+}
+
+func (s *PublicReservationService) List(req PublicReservationRequest) (*PublicReservationPage, error) {
+	// Sin from explícito se redondea al minuto para que el ETag sea estable
+	from := time.Now().Truncate(time.Minute)
+	if req.From != nil {
+		from = *req.From
+	}
+	to := from.Add(publicDefaultRange)
+	if req.To != nil {
+		to = *req.To
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidPublicQuery)
+	}
+	if to.Sub(from) > publicMaxRange {
+		return nil, fmt.Errorf("%w: range cannot exceed 31 days", ErrInvalidPublicQuery)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = publicDefaultLimit
+	}
+	if limit > publicMaxLimit {
+		limit = publicMaxLimit
+	}
+	q := repositories.PublicReservationQuery{
+		From: from, To: to, BuildingID: req.BuildingID, RoomID: req.RoomID, Limit: limit + 1,
+	}
+	if req.Cursor != "" {
+		start, id, err := decodePublicCursor(req.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidPublicQuery)
+		}
+		q.AfterStart, q.AfterID = &start, id
+	}
+
+	fields := s.fields
+	if len(req.Fields) > 0 {
+		fields = map[string]bool{}
+		for _, f := range req.Fields {
+			if s.fields[f] {
+				fields[f] = true
+			}
+		}
+	}
+
+	rows, err := s.repo.FindPublic(q)
+	if err != nil {
+		return nil, err
+	}
+	page := &PublicReservationPage{From: from, To: to, Items: make([]PublicReservation, 0, len(rows))}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodePublicCursor(last.StartTime, last.ID)
+	}
+	for i := range rows {
+		page.Items = append(page.Items, projectPublicReservation(&rows[i], fields))
+	}
+	return page, nil
+}
+
+func projectPublicReservation(r *repositories.PublicReservationRow, fields map[string]bool) PublicReservation {
+	p := PublicReservation{
+		ID:           r.ID,
+		RoomID:       r.RoomID,
+		RoomName:     r.RoomName,
+		BuildingID:   r.BuildingID,
+		BuildingName: r.BuildingName,
+		StartTime:    r.StartTime,
+		EndTime:      r.EndTime,
+		Private:      r.Private,
+	}
+	if r.Private {
+		return p
+	}
+	if fields["purpose"] {
+		p.Purpose = &r.Purpose
+	}
+	if fields["class"] && r.ClassName != "" {
+		p.ClassName = &r.ClassName
+	}
+	if fields["attendees"] {
+		p.EstimatedAttendees = &r.EstimatedAttendees
+	}
+	if fields["user_name"] {
+		p.UserName = &r.UserName
+	}
+	if fields["user_email"] {
+		p.UserEmail = &r.UserEmail
+	}
+	return p
+}
+
+// El cursor es opaco para el cliente: "<inicio en ns>.<id>" en base64 URL
+func encodePublicCursor(start time.Time, id uint) string {
+	raw := strconv.FormatInt(start.UnixNano(), 10) + "." + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePublicCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	parts := strings.SplitN(string(raw), ".", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, errors.New("malformed cursor")
+	}
+	ns, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return time.Unix(0, ns), uint(id), nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestPublicCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		id    uint
+	}{
+		{"hora exacta", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), 1},
+		{"con nanosegundos", time.Date(2026, 3, 2, 9, 0, 0, 123456789, time.UTC), 42},
+		{"id máximo", time.Date(2030, 12, 31, 23, 59, 59, 0, time.UTC), 1<<32 - 1},
+		{"antes de 1970", time.Date(1969, 7, 20, 20, 17, 0, 0, time.UTC), 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := encodePublicCursor(tt.start, tt.id)
+			start, id, err := decodePublicCursor(cursor)
+			if err != nil {
+				t.Fatalf("decode(%q): %v", cursor, err)
+			}
+			if !start.Equal(tt.start) || id != tt.id {
+				t.Errorf("decode(%q) = %v, %d, want %v, %d", cursor, start, id, tt.start, tt.id)
+			}
+		})
+	}
+}
+
+func TestDecodePublicCursorRejectsMalformed(t *testing.T) {
+	enc := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"no es base64", "%%%"},
+		{"base64 con relleno", enc("1.1") + "=="},
+		{"sin separador", enc("1700000000")},
+		{"inicio no numérico", enc("ayer.5")},
+		{"id no numérico", enc("1700000000.x")},
+		{"id negativo", enc("1700000000.-1")},
+		{"id fuera de rango", enc("1700000000.4294967296")},
+		{"id vacío", enc("1700000000.")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodePublicCursor(tt.cursor); err == nil {
+				t.Errorf("decode(%q) succeeded, want error", tt.cursor)
+			}
+		})
+	}
+}
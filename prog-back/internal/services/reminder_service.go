@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Las claves de recordatorios se conservan una semana más que el mayor aviso
+const reminderClaimRetention = 8 * 24 * time.Hour
+
+type ReminderService struct {
+	resvRepo      *repositories.ReservationRepository
+	claimRepo     *repositories.ReminderRepository
+	notifications *NotificationService
+}
+
+func NewReminderService(cfg *config.Config) *ReminderService {
+	return &ReminderService{
+		resvRepo:      repositories.NewReservationRepository(),
+		claimRepo:     repositories.NewReminderRepository(),
+		notifications: NewNotificationService(cfg),
+	}
+}
+
+// SendReminders avisa al titular de cada reserva próxima. offsets va de mayor
+// a menor (ej. 24h, 1h), como los deja config.Load. Para cada reserva sólo se envía el aviso del menor
+// offset que todavía la alcanza: tras un reinicio no se mandan avisos atrasados
+// que ya perdieron sentido. Devuelve la cantidad de recordatorios enviados.
+func (s *ReminderService) SendReminders(offsets []time.Duration, now time.Time) (int, error) {
+	if len(offsets) == 0 {
+		return 0, nil
+	}
+	list, err := s.resvRepo.FindActiveStartingBetween(now, now.Add(offsets[0]))
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, r := range list {
+		offset := offsets[0]
+		for _, o := range offsets[1:] {
+			if r.StartTime.Sub(now) <= o {
+				offset = o
+			}
+		}
+		err := s.claimAndNotify(fmt.Sprintf("reminder:%d:%s", r.ID, offset), EventReservationReminder, r.UserID, map[string]string{
+			"Purpose": r.Purpose,
+			"Room":    reservationRoomName(&r),
+			"Start":   r.StartTime.Local().Format("02/01/2006 15:04"),
+		})
+		if errors.Is(err, errAlreadyClaimed) {
+			continue
+		}
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// SendDigests envía a cada usuario la agenda de sus reservas del día, una vez
+// por día a partir de la hora indicada (hora local)
+func (s *ReminderService) SendDigests(hour int, now time.Time) (int, error) {
+	local := now.Local()
+	if local.Hour() < hour {
+		return 0, nil
+	}
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	list, err := s.resvRepo.FindActiveStartingBetween(now, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	byUser := map[uint][]models.Reservation{}
+	var users []uint
+	for _, r := range list {
+		if _, ok := byUser[r.UserID]; !ok {
+			users = append(users, r.UserID)
+		}
+		byUser[r.UserID] = append(byUser[r.UserID], r)
+	}
+
+	sent := 0
+	date := dayStart.Format("2006-01-02")
+	for _, uid := range users {
+		var items []string
+		for _, r := range byUser[uid] {
+			items = append(items, fmt.Sprintf("- %s-%s %s (%s)",
+				r.StartTime.Local().Format("15:04"), r.EndTime.Local().Format("15:04"),
+				r.Purpose, reservationRoomName(&r)))
+		}
+		err := s.claimAndNotify(fmt.Sprintf("digest:%d:%s", uid, date), EventAgendaDigest, uid, map[string]string{
+			"Date":  dayStart.Format("02/01/2006"),
+			"Count": fmt.Sprint(len(items)),
+			"Items": strings.Join(items, "\n"),
+		})
+		if errors.Is(err, errAlreadyClaimed) {
+			continue
+		}
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+var errAlreadyClaimed = errors.New("already claimed")
+
+// claimAndNotify reclama la clave y encola la notificación. Si no se pudo
+// encolar libera la clave, para que el envío no se pierda: la próxima pasada
+// lo reintenta. Devuelve errAlreadyClaimed si otro ya lo envió.
+func (s *ReminderService) claimAndNotify(key, event string, userID uint, data map[string]string) error {
+	ok, err := s.claimRepo.Claim(key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errAlreadyClaimed
+	}
+	if err := s.notifications.enqueue(event, []uint{userID}, data); err != nil {
+		if relErr := s.claimRepo.Release(key); relErr != nil {
+			log.WithError(relErr).WithField("key", key).Error("No se pudo liberar el recordatorio")
+		}
+		return err
+	}
+	return nil
+}
+
+// PruneClaims borra las claves que ya no pueden repetirse
+func (s *ReminderService) PruneClaims(now time.Time) error {
+	return s.claimRepo.DeleteOlderThan(now.Add(-reminderClaimRetention))
+}
+
+func reservationRoomName(r *models.Reservation) string {
+	if r.Room != nil {
+		return r.Room.Name
+	}
+	return fmt.Sprintf("aula %d", r.RoomID)
+}
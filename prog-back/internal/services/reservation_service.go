@@ -6,29 +6,88 @@ import (
 
 	"programcion-backend/internal/models"
 	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
 )
 
 type ReservationService struct {
-	repo     *repositories.ReservationRepository
-	roomRepo *repositories.RoomRepository
+	repo      *repositories.ReservationRepository
+	roomRepo  *repositories.RoomRepository
+	classRepo *repositories.ClassRepository
+	termRepo  *repositories.TermRepository
+
+	announcements *AnnouncementService
+	notifications *NotificationService
+	people        *personConflictChecker
 }
 
-func NewReservationService() *ReservationService {
+func NewReservationService(cfg *config.Config) *ReservationService {
 	return &ReservationService{
-		repo:     repositories.NewReservationRepository(),
-		roomRepo: repositories.NewRoomRepository(),
+		repo:      repositories.NewReservationRepository(),
+		roomRepo:  repositories.NewRoomRepository(),
+		classRepo: repositories.NewClassRepository(),
+		termRepo:  repositories.NewTermRepository(),
+
+		announcements: NewAnnouncementService(cfg),
+		notifications: NewNotificationService(cfg),
+		people:        newPersonConflictChecker(cfg.Reservations),
 	}
 }
 
 func (s *ReservationService) Create(resv *models.Reservation) error {
-	// Validaciones: no solapamiento y capacidad
+	if err := s.validate(resv); err != nil {
+		return err
+	}
+	resv.Status = "ACTIVE"
+	return s.repo.Create(resv)
+}
+
+// validate aplica las validaciones comunes: período académico, no solapamiento y capacidad
+func (s *ReservationService) validate(resv *models.Reservation) error {
+	// Las reservas de una clase heredan su período académico
+	var class *models.Class
+	if resv.ClassID != nil {
+		var err error
+		class, err = s.classRepo.FindByID(*resv.ClassID)
+		if err != nil {
+			return errors.New("class not found")
+		}
+		if class.ArchivedAt != nil {
+			return errors.New("class is archived")
+		}
+		if resv.TermID == nil {
+			resv.TermID = class.TermID
+		}
+	}
+	if resv.TermID != nil {
+		term, err := s.termRepo.GetByID(*resv.TermID)
+		if err != nil {
+			return errors.New("term not found")
+		}
+		if err := checkTermBounds(term, resv.StartTime, resv.EndTime); err != nil {
+			return err
+		}
+	}
+
 	overlaps, err := s.repo.HasOverlapping(resv.RoomID, resv.StartTime, resv.EndTime)
 	if err != nil {
 		return err
 	}
 	if overlaps {
-		return errors.New("time slot not available (overlap)")
+		return repositories.ErrSlotTaken
+	}
+
+	// El usuario y los docentes de la clase no pueden estar en dos lugares a la
+	// vez. Las reservas de cartelería no ocupan a nadie: el titular es sólo el
+	// admin que creó el token.
+	person := resv.UserID
+	if resv.SignageTokenID != nil {
+		person = 0
 	}
+	warnings, err := s.people.Check(person, class, resv.StartTime, resv.EndTime, 0)
+	if err != nil {
+		return err
+	}
+	resv.Warnings = warnings
 
 	// Verificar capacidad del aula
 	room, err := s.roomRepo.GetByID(resv.RoomID)
@@ -38,11 +97,56 @@ func (s *ReservationService) Create(resv *models.Reservation) error {
 	if resv.EstimatedAttendees > room.Capacity {
 		return errors.New("estimated attendees exceeds room capacity")
 	}
+	return nil
+}
 
-	resv.Status = "ACTIVE"
+// Duración del bloqueo temporal mientras el usuario completa la reserva
+const holdTTL = 5 * time.Minute
+
+// PlaceHold bloquea el horario de forma provisional. El bloqueo cuenta para
+// la validación de solapamiento hasta que se confirma o vence.
+func (s *ReservationService) PlaceHold(resv *models.Reservation) error {
+	if err := s.validate(resv); err != nil {
+		return err
+	}
+	expires := time.Now().Add(holdTTL)
+	resv.Status = "HELD"
+	resv.HoldExpiresAt = &expires
 	return s.repo.Create(resv)
 }
 
+// ConfirmHold convierte un bloqueo vigente en una reserva definitiva
+func (s *ReservationService) ConfirmHold(id, userID uint) (*models.Reservation, error) {
+	resv, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("hold not found")
+	}
+	if resv.UserID != userID {
+		return nil, errors.New("not the hold owner")
+	}
+	if err := s.repo.ConfirmHold(id, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// ReleaseHold libera un bloqueo antes de su vencimiento
+func (s *ReservationService) ReleaseHold(id, userID uint) error {
+	resv, err := s.repo.GetByID(id)
+	if err != nil || resv.Status != "HELD" {
+		return errors.New("hold not found")
+	}
+	if resv.UserID != userID {
+		return errors.New("not the hold owner")
+	}
+	resv.Status = "EXPIRED"
+	return s.repo.Update(resv, models.EventReservationExpired)
+}
+
+func (s *ReservationService) ExpireHolds() (int64, error) {
+	return s.repo.ExpireHolds(time.Now())
+}
+
 func (s *ReservationService) List(filter map[string]interface{}, from, to *time.Time) ([]models.Reservation, error) {
 	return s.repo.List(filter, from, to)
 }
@@ -56,6 +160,92 @@ func (s *ReservationService) Cancel(id uint) error {
 	if err != nil {
 		return err
 	}
+	wasActive := resv.Status == "ACTIVE"
+	event := ""
+	if wasActive {
+		event = models.EventReservationCancelled
+	}
 	resv.Status = "CANCELLED"
-	return s.repo.Update(resv)
+	if err := s.repo.Update(resv, event); err != nil {
+		return err
+	}
+	// Avisar a los estudiantes si se cancela una sesión de clase futura
+	if wasActive && resv.ClassID != nil && resv.StartTime.After(time.Now()) {
+		s.announcements.SessionCancelled(resv)
+	}
+	if wasActive {
+		s.notifications.Notify(EventReservationCancelled, []uint{resv.UserID}, map[string]string{
+			"Purpose": resv.Purpose,
+			"Room":    s.announcements.roomName(resv.RoomID),
+			"Start":   resv.StartTime.Local().Format("02/01/2006 15:04"),
+		})
+	}
+	return nil
+}
+
+// Margen antes del inicio en el que ya se permite hacer check-in
+const checkinEarlyWindow = 15 * time.Minute
+
+// CheckIn registra la presencia en una reserva. Solo el titular (o un admin)
+// puede hacerlo, y únicamente dentro de la ventana de la reserva.
+func (s *ReservationService) CheckIn(id, userID uint, isAdmin bool) (*models.Reservation, error) {
+	resv, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("reservation not found")
+	}
+	if !isAdmin && resv.UserID != userID {
+		return nil, errors.New("not the reservation owner")
+	}
+	if resv.Status != "ACTIVE" {
+		return nil, errors.New("reservation is not active")
+	}
+	if resv.CheckedInAt != nil {
+		return resv, nil
+	}
+	now := time.Now()
+	if now.Before(resv.StartTime.Add(-checkinEarlyWindow)) || !now.Before(resv.EndTime) {
+		return nil, errors.New("outside check-in window")
+	}
+	resv.CheckedInAt = &now
+	if err := s.repo.Update(resv, models.EventReservationCheckedIn); err != nil {
+		return nil, err
+	}
+	return resv, nil
+}
+
+// CheckInByRoomToken hace check-in en la reserva en curso del usuario en el
+// aula identificada por el token de su QR
+func (s *ReservationService) CheckInByRoomToken(token string, userID uint) (*models.Reservation, error) {
+	if token == "" {
+		return nil, errors.New("invalid check-in token")
+	}
+	room, err := s.roomRepo.GetByCheckinToken(token)
+	if err != nil {
+		return nil, errors.New("invalid check-in token")
+	}
+	resv, err := s.repo.FindCheckinCandidate(room.ID, userID, time.Now(), checkinEarlyWindow)
+	if err != nil {
+		return nil, errors.New("no reservation to check in for this room")
+	}
+	now := time.Now()
+	resv.CheckedInAt = &now
+	if err := s.repo.Update(resv, models.EventReservationCheckedIn); err != nil {
+		return nil, err
+	}
+	return resv, nil
+}
+
+// Sólo se liberan reservas que empezaron dentro de esta ventana: las más
+// viejas ya terminaron o quedaron fuera del alcance del job
+const noShowWindow = 24 * time.Hour
+
+// ReleaseNoShows libera las reservas puntuales sin check-in pasados grace
+// minutos del inicio, mientras sigan en curso
+func (s *ReservationService) ReleaseNoShows(grace time.Duration) (int64, error) {
+	now := time.Now()
+	return s.repo.MarkNoShows(now.Add(-noShowWindow), now.Add(-grace), now)
+}
+
+func (s *ReservationService) NoShowReport(from, to *time.Time) ([]repositories.NoShowCount, error) {
+	return s.repo.CountNoShowsByUser(from, to)
 }
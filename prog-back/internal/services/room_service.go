@@ -1,8 +1,12 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+
 	"programcion-backend/internal/models"
 	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/utils"
 )
 
 type RoomService struct {
@@ -15,7 +19,24 @@ func NewRoomService() *RoomService {
 	}
 }
 
+var ErrInvalidRoom = errors.New("invalid room")
+
+// validateRoom verifica las capacidades: las plazas de examen no pueden
+// superar la capacidad del aula
+func validateRoom(room *models.Room) error {
+	if room.Capacity < 0 || room.ExamCapacity < 0 {
+		return fmt.Errorf("%w: capacity cannot be negative", ErrInvalidRoom)
+	}
+	if room.ExamCapacity > room.Capacity {
+		return fmt.Errorf("%w: exam_capacity cannot exceed capacity", ErrInvalidRoom)
+	}
+	return nil
+}
+
 func (s *RoomService) Create(room *models.Room) error {
+	if err := validateRoom(room); err != nil {
+		return err
+	}
 	return s.repo.Create(room)
 }
 
@@ -29,9 +50,34 @@ func (s *RoomService) Get(id uint) (*models.Room, error) {
 }
 
 func (s *RoomService) Update(room *models.Room) error {
+	if err := validateRoom(room); err != nil {
+		return err
+	}
+	// El payload no trae el token de check-in; conservar el existente
+	if existing, err := s.repo.GetByID(room.ID); err == nil {
+		room.CheckinToken = existing.CheckinToken
+	}
 	return s.repo.Update(room)
 }
 
 func (s *RoomService) Delete(id uint) error {
 	return s.repo.Delete(id)
 }
+
+// RegenerateCheckinToken genera un nuevo token para el QR de check-in del aula,
+// invalidando el anterior
+func (s *RoomService) RegenerateCheckinToken(id uint) (string, error) {
+	room, err := s.repo.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	token, err := utils.GenerateToken(16)
+	if err != nil {
+		return "", err
+	}
+	room.CheckinToken = token
+	if err := s.repo.Update(room); err != nil {
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"programcion-backend/internal/models"
+)
+
+func TestValidateRoom(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacity     int
+		examCapacity int
+		wantErr      bool
+	}{
+		{"sin plazas de examen", 40, 0, false},
+		{"plazas de examen menores", 40, 20, false},
+		{"plazas de examen iguales", 40, 40, false},
+		{"plazas de examen mayores", 40, 41, true},
+		{"capacidad negativa", -1, 0, true},
+		{"plazas de examen negativas", 40, -5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRoom(&models.Room{Capacity: tt.capacity, ExamCapacity: tt.examCapacity})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRoom() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRoom) {
+				t.Errorf("err = %v, want ErrInvalidRoom", err)
+			}
+		})
+	}
+}
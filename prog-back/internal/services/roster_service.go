@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/utils"
+)
+
+// Resultado por fila de la importación de padrón
+type RosterImportResult struct {
+	Row       int    `json:"row"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	StudentID uint   `json:"student_id,omitempty"`
+	Status    string `json:"status"` // enrolled|waitlisted|already_enrolled|not_a_student|unconfirmed|not_found|invited|invalid|error
+	Error     string `json:"error,omitempty"`
+}
+
+// ImportRoster inscribe a los estudiantes de un CSV con columnas email y name
+// (con o sin encabezado). Con invite=true los correos desconocidos se registran
+// como estudiantes sin confirmar y reciben por correo un código para elegir su
+// contraseña; no se inscriben hasta que un admin los confirme.
+func (s *ClassService) ImportRoster(classID uint, r io.Reader, invite bool) ([]RosterImportResult, error) {
+	if err := s.ensureWritable(classID); err != nil {
+		return nil, err
+	}
+	if invite && !s.notifications.EmailEnabled() {
+		return nil, errors.New("invites require email delivery (SMTP_HOST is not set)")
+	}
+	rows, err := parseRoster(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		if rows[i].Status == "" {
+			s.importRow(classID, &rows[i], invite)
+		}
+	}
+	return rows, nil
+}
+
+// parseRoster lee las filas del CSV de padrón. Las filas vacías se omiten y
+// las de correo inválido vuelven ya marcadas como invalid; el resto queda sin
+// estado para que ImportRoster las procese.
+func parseRoster(r io.Reader) ([]RosterImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New("invalid CSV: " + err.Error())
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty CSV")
+	}
+
+	emailCol, nameCol, start := 0, 1, 0
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "email", "correo":
+			emailCol, start = i, 1
+		case "name", "nombre":
+			nameCol = i
+		}
+	}
+
+	results := make([]RosterImportResult, 0, len(records)-start)
+	for i := start; i < len(records); i++ {
+		rec := records[i]
+		res := RosterImportResult{Row: i + 1}
+		if emailCol < len(rec) {
+			res.Email = strings.ToLower(strings.TrimSpace(rec[emailCol]))
+		}
+		if nameCol < len(rec) {
+			res.Name = strings.TrimSpace(rec[nameCol])
+		}
+		if res.Email == "" && res.Name == "" {
+			continue
+		}
+		if !strings.Contains(res.Email, "@") {
+			res.Status = "invalid"
+			res.Error = "invalid email"
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *ClassService) importRow(classID uint, res *RosterImportResult, invite bool) {
+	user, err := s.userRepo.GetByEmail(res.Email)
+	if err != nil {
+		if !invite {
+			res.Status = "not_found"
+			return
+		}
+		if res.Name == "" {
+			res.Status = "invalid"
+			res.Error = "name required to invite"
+			return
+		}
+		invited, err := s.inviteStudent(classID, res.Name, res.Email)
+		if err != nil {
+			res.Status = "error"
+			res.Error = err.Error()
+			return
+		}
+		res.StudentID = invited.ID
+		res.Status = "invited"
+		return
+	}
+	res.StudentID = user.ID
+
+	if cs, err := s.repo.GetEnrollment(classID, user.ID); err == nil && cs.Status == "ENROLLED" {
+		res.Status = "already_enrolled"
+		return
+	}
+	status, err := s.AddStudent(classID, user.ID)
+	switch {
+	case errors.Is(err, errNotAStudent):
+		res.Status = "not_a_student"
+	case errors.Is(err, errStudentUnconfirmed):
+		res.Status = "unconfirmed"
+	case err != nil:
+		res.Status = "error"
+		res.Error = err.Error()
+	default:
+		res.Status = strings.ToLower(status)
+	}
+}
+
+// inviteStudent registra una cuenta de estudiante sin confirmar, con una
+// contraseña aleatoria que nadie conoce, y le envía por correo el código de
+// invitación con el que elige la suya (POST /api/auth/accept-invite). La
+// invitación guarda la clase: se lo inscribe cuando el admin confirma la cuenta.
+func (s *ClassService) inviteStudent(classID uint, name, email string) (*models.User, error) {
+	class, err := s.repo.FindByID(classID)
+	if err != nil {
+		return nil, errors.New("class not found")
+	}
+	pwd, err := utils.GenerateToken(16)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := utils.HashPassword(pwd)
+	if err != nil {
+		return nil, err
+	}
+	token, err := utils.GenerateToken(24)
+	if err != nil {
+		return nil, err
+	}
+	invite := &models.UserInvite{TokenHash: hashInviteToken(token), ClassID: &classID, ExpiresAt: time.Now().Add(inviteTTL)}
+	u := &models.User{Name: name, Email: email, PasswordHash: hash, Role: "STUDENT", IsConfirmed: false}
+	if err := s.userRepo.CreateInvited(u, invite); err != nil {
+		return nil, err
+	}
+	err = s.notifications.enqueue(EventAccountInvited, []uint{u.ID}, map[string]string{
+		"Name":    u.Name,
+		"Email":   u.Email,
+		"Class":   class.Name,
+		"Token":   token,
+		"Expires": invite.ExpiresAt.Local().Format("02/01/2006 15:04"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("account created but the invitation could not be queued: %w", err)
+	}
+	return u, nil
+}
+
+func (s *ClassService) GetRoster(classID uint) ([]repositories.RosterEntry, error) {
+	return s.repo.GetRoster(classID)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRoster(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []RosterImportResult
+		wantErr string
+	}{
+		{
+			name: "sin encabezado",
+			csv:  "ana@uni.edu,Ana Pérez\nluis@uni.edu,Luis Gómez\n",
+			want: []RosterImportResult{
+				{Row: 1, Email: "ana@uni.edu", Name: "Ana Pérez"},
+				{Row: 2, Email: "luis@uni.edu", Name: "Luis Gómez"},
+			},
+		},
+		{
+			name: "encabezado con columnas invertidas",
+			csv:  "Nombre,Correo\nAna Pérez,ana@uni.edu\n",
+			want: []RosterImportResult{
+				{Row: 2, Email: "ana@uni.edu", Name: "Ana Pérez"},
+			},
+		},
+		{
+			name: "normaliza correo y espacios",
+			csv:  "email,name\n  ANA@Uni.EDU ,  Ana  \n",
+			want: []RosterImportResult{
+				{Row: 2, Email: "ana@uni.edu", Name: "Ana"},
+			},
+		},
+		{
+			name: "omite filas vacías y marca correos inválidos",
+			csv:  "email,name\n,\nsin-arroba,Pedro\nluis@uni.edu\n",
+			want: []RosterImportResult{
+				{Row: 3, Email: "sin-arroba", Name: "Pedro", Status: "invalid", Error: "invalid email"},
+				{Row: 4, Email: "luis@uni.edu"},
+			},
+		},
+		{
+			name: "columnas extra",
+			csv:  "legajo,email,name,carrera\n123,ana@uni.edu,Ana,Sistemas\n",
+			want: []RosterImportResult{
+				{Row: 2, Email: "ana@uni.edu", Name: "Ana"},
+			},
+		},
+		{
+			name:    "vacío",
+			csv:     "",
+			wantErr: "empty CSV",
+		},
+		{
+			name:    "comillas mal cerradas",
+			csv:     "email,name\n\"ana@uni.edu,Ana\n",
+			wantErr: "invalid CSV",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRoster(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
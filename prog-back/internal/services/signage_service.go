@@ -0,0 +1,305 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+	"programcion-backend/pkg/utils"
+)
+
+const (
+	signageLookahead       = 24 * time.Hour
+	instantBookingDuration = 30 * time.Minute
+)
+
+var (
+	errInvalidSignageToken    = errors.New("invalid signage token")
+	ErrSignageScope           = errors.New("signage token does not cover this resource")
+	ErrInstantBookingOff      = errors.New("instant booking is not allowed in this room")
+	ErrRoomNotFree            = errors.New("room is not free for the next 30 minutes")
+	ErrInstantBookingRejected = errors.New("instant booking rejected")
+)
+
+// SignageBooking es la vista de una reserva en la pantalla: sin datos de quien reservó
+type SignageBooking struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"` // nombre de la clase o motivo de la reserva
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	CheckedIn bool      `json:"checked_in"`
+}
+
+type RoomBoard struct {
+	RoomID       uint            `json:"room_id"`
+	RoomName     string          `json:"room_name"`
+	BuildingID   uint            `json:"building_id"`
+	BuildingName string          `json:"building_name"`
+	Capacity     int             `json:"capacity"`
+	Now          time.Time       `json:"now"`
+	Current      *SignageBooking `json:"current"`
+	Next         *SignageBooking `json:"next"`
+	FreeNow      bool            `json:"free_now"`
+	FreeUntil    *time.Time      `json:"free_until,omitempty"`   // libre hasta el próximo turno (nulo = al menos 24 h)
+	FreeMinutes  *int            `json:"free_minutes,omitempty"` // minutos libres desde ahora
+	BusyUntil    *time.Time      `json:"busy_until,omitempty"`   // fin de las reservas encadenadas en curso
+	// InstantBooking indica si ahora se puede reservar el aula por 30 minutos
+	InstantBooking bool `json:"instant_booking"`
+}
+
+type BuildingBoard struct {
+	BuildingID   uint        `json:"building_id"`
+	BuildingName string      `json:"building_name"`
+	Now          time.Time   `json:"now"`
+	Rooms        []RoomBoard `json:"rooms"`
+}
+
+// SignageTokenCreated incluye el valor del token, que sólo se muestra al crearlo
+type SignageTokenCreated struct {
+	models.SignageToken
+	Token string `json:"token"`
+}
+
+type SignageService struct {
+	repo         *repositories.SignageRepository
+	roomRepo     *repositories.RoomRepository
+	buildingRepo *repositories.BuildingRepository
+	resvRepo     *repositories.ReservationRepository
+	termRepo     *repositories.TermRepository
+	reservations *ReservationService
+}
+
+func NewSignageService(cfg *config.Config) *SignageService {
+	return &SignageService{
+		repo:         repositories.NewSignageRepository(),
+		roomRepo:     repositories.NewRoomRepository(),
+		buildingRepo: repositories.NewBuildingRepository(),
+		resvRepo:     repositories.NewReservationRepository(),
+		termRepo:     repositories.NewTermRepository(),
+		reservations: NewReservationService(cfg),
+	}
+}
+
+func hashSignageToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken crea un token para un aula o para un edificio (exactamente uno).
+// Las reservas instantáneas hechas con el token quedan a nombre de createdBy.
+func (s *SignageService) CreateToken(name string, roomID, buildingID *uint, createdBy uint) (*SignageTokenCreated, error) {
+	if (roomID == nil) == (buildingID == nil) {
+		return nil, errors.New("exactly one of room_id or building_id is required")
+	}
+	if roomID != nil {
+		if _, err := s.roomRepo.GetByID(*roomID); err != nil {
+			return nil, errors.New("room not found")
+		}
+	}
+	if buildingID != nil {
+		if _, err := s.buildingRepo.GetByID(*buildingID); err != nil {
+			return nil, errors.New("building not found")
+		}
+	}
+	token, err := utils.GenerateToken(24)
+	if err != nil {
+		return nil, err
+	}
+	t := models.SignageToken{
+		Name:        strings.TrimSpace(name),
+		TokenHash:   hashSignageToken(token),
+		RoomID:      roomID,
+		BuildingID:  buildingID,
+		CreatedByID: &createdBy,
+	}
+	if err := s.repo.Create(&t); err != nil {
+		return nil, err
+	}
+	return &SignageTokenCreated{SignageToken: t, Token: token}, nil
+}
+
+func (s *SignageService) ListTokens() ([]models.SignageToken, error) {
+	return s.repo.List()
+}
+
+func (s *SignageService) RevokeToken(id uint) error {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return errors.New("signage token not found")
+	}
+	return s.repo.Revoke(id, time.Now())
+}
+
+// Authenticate valida el token de la pantalla y registra su último uso
+func (s *SignageService) Authenticate(token string) (*models.SignageToken, error) {
+	if token == "" {
+		return nil, errInvalidSignageToken
+	}
+	t, err := s.repo.FindActiveByHash(hashSignageToken(token))
+	if err != nil {
+		return nil, errInvalidSignageToken
+	}
+	now := time.Now()
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) > time.Minute {
+		_ = s.repo.TouchLastUsed(t.ID, now)
+	}
+	return t, nil
+}
+
+// covers indica si el token da acceso al aula: el de un aula sólo a ella, el
+// de un edificio a todas sus aulas
+func (s *SignageService) covers(t *models.SignageToken, room *models.Room) bool {
+	if t.RoomID != nil {
+		return *t.RoomID == room.ID
+	}
+	return t.BuildingID != nil && *t.BuildingID == room.BuildingID
+}
+
+func (s *SignageService) RoomBoard(t *models.SignageToken, roomID uint) (*RoomBoard, error) {
+	room, err := s.roomRepo.GetByID(roomID)
+	if err != nil {
+		return nil, errors.New("room not found")
+	}
+	if !s.covers(t, room) {
+		return nil, ErrSignageScope
+	}
+	now := time.Now()
+	agenda, err := s.resvRepo.FindRoomAgenda([]uint{room.ID}, now, now.Add(signageLookahead))
+	if err != nil {
+		return nil, err
+	}
+	board := buildRoomBoard(room, agenda, now)
+	return &board, nil
+}
+
+func (s *SignageService) BuildingBoard(t *models.SignageToken, buildingID uint) (*BuildingBoard, error) {
+	if t.BuildingID == nil || *t.BuildingID != buildingID {
+		return nil, ErrSignageScope
+	}
+	building, err := s.buildingRepo.GetByID(buildingID)
+	if err != nil {
+		return nil, errors.New("building not found")
+	}
+	rooms, err := s.roomRepo.FindByBuildingID(buildingID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(rooms))
+	for _, r := range rooms {
+		ids = append(ids, r.ID)
+	}
+	now := time.Now()
+	agenda, err := s.resvRepo.FindRoomAgenda(ids, now, now.Add(signageLookahead))
+	if err != nil {
+		return nil, err
+	}
+	byRoom := map[uint][]models.Reservation{}
+	for _, r := range agenda {
+		byRoom[r.RoomID] = append(byRoom[r.RoomID], r)
+	}
+	out := &BuildingBoard{BuildingID: building.ID, BuildingName: building.Name, Now: now, Rooms: []RoomBoard{}}
+	for i := range rooms {
+		out.Rooms = append(out.Rooms, buildRoomBoard(&rooms[i], byRoom[rooms[i].ID], now))
+	}
+	return out, nil
+}
+
+// buildRoomBoard calcula el turno actual, el siguiente y el tiempo libre a
+// partir de la agenda del aula ordenada por inicio
+func buildRoomBoard(room *models.Room, agenda []models.Reservation, now time.Time) RoomBoard {
+	b := RoomBoard{RoomID: room.ID, RoomName: room.Name, BuildingID: room.BuildingID, Capacity: room.Capacity, Now: now, FreeNow: true}
+	if room.Building != nil {
+		b.BuildingName = room.Building.Name
+	}
+	for i := range agenda {
+		r := &agenda[i]
+		if !r.StartTime.After(now) && r.EndTime.After(now) {
+			if b.Current == nil {
+				b.Current = signageBooking(r)
+			}
+			continue
+		}
+		if r.StartTime.After(now) && b.Next == nil {
+			b.Next = signageBooking(r)
+		}
+	}
+	if b.Current != nil {
+		b.FreeNow = false
+		busy := b.Current.EndTime
+		for _, r := range agenda {
+			if !r.StartTime.After(busy) && r.EndTime.After(busy) {
+				busy = r.EndTime
+			}
+		}
+		b.BusyUntil = &busy
+		return b
+	}
+	if b.Next != nil {
+		until := b.Next.StartTime
+		minutes := int(until.Sub(now).Minutes())
+		b.FreeUntil = &until
+		b.FreeMinutes = &minutes
+	}
+	b.InstantBooking = room.InstantBooking && (b.Next == nil || !b.Next.StartTime.Before(now.Add(instantBookingDuration)))
+	return b
+}
+
+func signageBooking(r *models.Reservation) *SignageBooking {
+	title := r.Purpose
+	if r.Class != nil {
+		title = r.Class.Name
+	}
+	if r.Private {
+		title = privateReservationTitle
+	}
+	return &SignageBooking{ID: r.ID, Title: title, StartTime: r.StartTime, EndTime: r.EndTime, CheckedIn: r.CheckedInAt != nil}
+}
+
+// InstantBook reserva el aula por 30 minutos desde ahora si la política del
+// aula lo permite y está libre. Quien reserva está en la puerta, así que la
+// reserva nace con check-in. Pasa por las mismas validaciones que cualquier
+// reserva (feriados del período vigente, solapamiento bajo el lock del aula) y
+// queda marcada con el token como origen.
+func (s *SignageService) InstantBook(t *models.SignageToken, roomID uint, purpose string) (*models.Reservation, error) {
+	room, err := s.roomRepo.GetByID(roomID)
+	if err != nil {
+		return nil, errors.New("room not found")
+	}
+	if !s.covers(t, room) {
+		return nil, ErrSignageScope
+	}
+	if !room.InstantBooking || t.CreatedByID == nil {
+		return nil, ErrInstantBookingOff
+	}
+	now := time.Now()
+	start := now.Truncate(time.Minute)
+	end := start.Add(instantBookingDuration)
+	if purpose = strings.TrimSpace(purpose); purpose == "" {
+		purpose = "Reserva instantánea"
+	}
+	resv := &models.Reservation{
+		RoomID:         room.ID,
+		UserID:         *t.CreatedByID,
+		SignageTokenID: &t.ID,
+		StartTime:      start,
+		EndTime:        end,
+		Purpose:        purpose,
+		CheckedInAt:    &now,
+	}
+	if term, err := s.termRepo.FindCovering(start); err == nil {
+		resv.TermID = &term.ID
+	}
+	err = s.reservations.Create(resv)
+	switch {
+	case errors.Is(err, repositories.ErrSlotTaken):
+		return nil, ErrRoomNotFree
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrInstantBookingRejected, err)
+	}
+	return resv, nil
+}
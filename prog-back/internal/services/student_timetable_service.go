@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+)
+
+type StudentTimetableService struct {
+	classRepo *repositories.ClassRepository
+	resvRepo  *repositories.ReservationRepository
+}
+
+func NewStudentTimetableService() *StudentTimetableService {
+	return &StudentTimetableService{
+		classRepo: repositories.NewClassRepository(),
+		resvRepo:  repositories.NewReservationRepository(),
+	}
+}
+
+// Rango máximo consultable para no devolver un semestre entero por error
+const maxTimetableRange = 180 * 24 * time.Hour
+
+// StudentTimetableEntry es una reserva de clase del horario del estudiante.
+// Clashes lista las reservas de otras de sus clases que se solapan con ésta.
+type StudentTimetableEntry struct {
+	models.Reservation
+	Clashes []uint `json:"clashes,omitempty"`
+}
+
+// Get combina las reservas de todas las clases en que el estudiante está inscrito
+func (s *StudentTimetableService) Get(studentID uint, from, to time.Time) ([]StudentTimetableEntry, error) {
+	if !to.After(from) {
+		return nil, errors.New("to must be after from")
+	}
+	if to.Sub(from) > maxTimetableRange {
+		return nil, errors.New("range too large (max 180 days)")
+	}
+	classes, err := s.classRepo.FindClassesByStudentID(studentID, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(classes))
+	for _, c := range classes {
+		ids = append(ids, c.ID)
+	}
+	resvs, err := s.resvRepo.FindStudentSchedule(studentID, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]StudentTimetableEntry, len(resvs))
+	for i := range resvs {
+		entries[i].Reservation = resvs[i]
+	}
+	// Las reservas vienen ordenadas por inicio: basta comparar hacia adelante
+	// hasta la primera que empieza después del fin
+	for i := range entries {
+		for j := i + 1; j < len(entries) && entries[j].StartTime.Before(entries[i].EndTime); j++ {
+			if sameClass(entries[i].ClassID, entries[j].ClassID) {
+				continue
+			}
+			entries[i].Clashes = append(entries[i].Clashes, entries[j].ID)
+			entries[j].Clashes = append(entries[j].Clashes, entries[i].ID)
+		}
+	}
+	return entries, nil
+}
+
+func sameClass(a, b *uint) bool {
+	return a != nil && b != nil && *a == *b
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+)
+
+type TermService struct {
+	repo *repositories.TermRepository
+}
+
+func NewTermService() *TermService {
+	return &TermService{
+		repo: repositories.NewTermRepository(),
+	}
+}
+
+func (s *TermService) Create(t *models.Term) error {
+	if !t.EndDate.After(t.StartDate) {
+		return errors.New("end_date must be after start_date")
+	}
+	return s.repo.Create(t)
+}
+
+func (s *TermService) List() ([]models.Term, error) {
+	return s.repo.GetAll()
+}
+
+func (s *TermService) Get(id uint) (*models.Term, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *TermService) Update(t *models.Term) error {
+	if !t.EndDate.After(t.StartDate) {
+		return errors.New("end_date must be after start_date")
+	}
+	return s.repo.Update(t)
+}
+
+func (s *TermService) AddHoliday(termID uint, date time.Time, name string) (*models.TermHoliday, error) {
+	term, err := s.repo.GetByID(termID)
+	if err != nil {
+		return nil, errors.New("term not found")
+	}
+	if date.Before(term.StartDate) || date.After(term.EndDate) {
+		return nil, errors.New("holiday outside term dates")
+	}
+	h := &models.TermHoliday{TermID: termID, Date: date, Name: name}
+	if err := s.repo.AddHoliday(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *TermService) DeleteHoliday(termID, holidayID uint) error {
+	return s.repo.DeleteHoliday(termID, holidayID)
+}
+
+// ArchiveEnded archiva los períodos finalizados junto con sus clases
+func (s *TermService) ArchiveEnded() (int, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	terms, err := s.repo.FindEndedUnarchived(today)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range terms {
+		if err := s.repo.Archive(t.ID, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(terms), nil
+}
+
+// checkTermBounds verifica que el intervalo caiga dentro del período y no
+// coincida con un feriado
+func checkTermBounds(term *models.Term, start, end time.Time) error {
+	if term.ArchivedAt != nil {
+		return errors.New("term is archived")
+	}
+	// Las fechas del período son días locales y end_date es inclusiva
+	first, last := localDays(start, end)
+	if first.Before(calendarDate(term.StartDate)) || last.After(calendarDate(term.EndDate)) {
+		return errors.New("reservation outside term dates")
+	}
+	if holidayBetween(term, start, end) {
+		return errors.New("reservation falls on a term holiday")
+	}
+	return nil
+}
+
+// holidayBetween indica si algún día calendario (hora local) que toca el
+// intervalo [start, end) es feriado del período; una reserva que cruza la
+// medianoche ocupa los dos días
+func holidayBetween(term *models.Term, start, end time.Time) bool {
+	if len(term.Holidays) == 0 {
+		return false
+	}
+	first, last := localDays(start, end)
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if holidayOn(term, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// localDays devuelve el primer y el último día local que toca [start, end)
+func localDays(start, end time.Time) (time.Time, time.Time) {
+	first := localDate(start)
+	if !end.After(start) {
+		return first, first
+	}
+	return first, localDate(end.Add(-time.Nanosecond))
+}
+
+// localDate devuelve el día calendario de t en la zona del servidor, como
+// medianoche UTC para compararlo con las fechas (columnas date) de feriados
+func localDate(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return calendarDate(t)
+}
+
+// calendarDate descarta la hora y la zona conservando año, mes y día de t
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"programcion-backend/internal/models"
+)
+
+func TestCheckTermBoundsHolidays(t *testing.T) {
+	// Servidor en UTC-3: las fechas del período y de feriado son días locales
+	local := time.Local
+	time.Local = time.FixedZone("UTC-3", -3*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	at := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	term := &models.Term{
+		StartDate: date("2026-03-01"),
+		EndDate:   date("2026-07-31"),
+		Holidays:  []models.TermHoliday{{Date: date("2026-05-01")}, {Date: date("2026-05-25")}},
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantErr    bool
+	}{
+		{"día hábil", at("2026-04-30 10:00"), at("2026-04-30 12:00"), false},
+		{"dentro del feriado", at("2026-05-01 10:00"), at("2026-05-01 12:00"), true},
+		{"termina a medianoche antes del feriado", at("2026-04-30 22:00"), at("2026-05-01 00:00"), false},
+		{"cruza la medianoche hacia el feriado", at("2026-04-30 22:00"), at("2026-05-01 01:00"), true},
+		{"cruza la medianoche desde el feriado", at("2026-05-01 23:00"), at("2026-05-02 01:00"), true},
+		{"abarca varios días con un feriado en medio", at("2026-05-24 08:00"), at("2026-05-26 08:00"), true},
+		// 02:00 UTC del 2 de mayo sigue siendo 1 de mayo en la hora local
+		{"instante en UTC que localmente es feriado", time.Date(2026, 5, 2, 2, 0, 0, 0, time.UTC), time.Date(2026, 5, 2, 2, 30, 0, 0, time.UTC), true},
+		// 04:00 UTC del 2 de mayo es la 01:00 local del 2, día hábil
+		{"instante en UTC del día siguiente al feriado", time.Date(2026, 5, 2, 4, 0, 0, 0, time.UTC), time.Date(2026, 5, 2, 5, 0, 0, 0, time.UTC), false},
+		{"primer día del período", at("2026-03-01 00:00"), at("2026-03-01 02:00"), false},
+		// 22:00 local del 28 de febrero ya es 1 de marzo en UTC
+		{"la noche anterior al período", at("2026-02-28 22:00"), at("2026-02-28 23:00"), true},
+		{"termina a medianoche del primer día", at("2026-02-28 22:00"), at("2026-03-01 00:00"), true},
+		// 22:00 local del 31 de julio ya es 1 de agosto en UTC
+		{"la noche del último día del período", at("2026-07-31 22:00"), at("2026-07-31 23:00"), false},
+		{"termina a medianoche del último día", at("2026-07-31 22:00"), at("2026-08-01 00:00"), false},
+		{"cruza la medianoche fuera del período", at("2026-07-31 23:00"), at("2026-08-01 01:00"), true},
+		{"el día siguiente al período", at("2026-08-01 10:00"), at("2026-08-01 11:00"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTermBounds(term, tt.start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkTermBounds(%v, %v) = %v, wantErr %v", tt.start, tt.end, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+)
+
+type TimetableService struct {
+	repo        *repositories.TimetableRepository
+	classRepo   *repositories.ClassRepository
+	sessionRepo *repositories.ClassSessionRepository
+	roomRepo    *repositories.RoomRepository
+	termRepo    *repositories.TermRepository
+	resvRepo    *repositories.ReservationRepository
+	sessions    *ClassSessionService
+}
+
+func NewTimetableService(cfg *config.Config) *TimetableService {
+	return &TimetableService{
+		repo:        repositories.NewTimetableRepository(),
+		classRepo:   repositories.NewClassRepository(),
+		sessionRepo: repositories.NewClassSessionRepository(),
+		roomRepo:    repositories.NewRoomRepository(),
+		termRepo:    repositories.NewTermRepository(),
+		resvRepo:    repositories.NewReservationRepository(),
+		sessions:    NewClassSessionService(cfg),
+	}
+}
+
+// TimetableRequest describe qué clases ubicar y con qué restricciones
+type TimetableRequest struct {
+	TermID                  uint                      `json:"term_id" binding:"required"`
+	BuildingID              uint                      `json:"building_id"` // 0 = todas las aulas
+	Classes                 []TimetableClassRequest   `json:"classes" binding:"required,min=1,dive"`
+	Slots                   []TimetableSlot           `json:"slots" binding:"required,min=1,dive"`
+	ProfessorUnavailability []ProfessorUnavailability `json:"professor_unavailability" binding:"dive"`
+}
+
+type TimetableClassRequest struct {
+	ClassID           uint     `json:"class_id" binding:"required"`
+	SessionsPerWeek   int      `json:"sessions_per_week"` // por defecto 1
+	DurationMinutes   int      `json:"duration_minutes"`  // por defecto 90
+	Resources         []string `json:"resources"`         // recursos requeridos del aula (ej. "proyector")
+	PreferredRooms    []uint   `json:"preferred_rooms"`
+	PreferredWeekdays []int    `json:"preferred_weekdays"`
+}
+
+// TimetableSlot es una hora de inicio permitida
+type TimetableSlot struct {
+	Weekday   int    `json:"weekday" binding:"min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required"` // HH:MM
+}
+
+type ProfessorUnavailability struct {
+	ProfessorID uint   `json:"professor_id" binding:"required"`
+	Weekday     int    `json:"weekday" binding:"min=0,max=6"`
+	StartTime   string `json:"start_time" binding:"required"`
+	EndTime     string `json:"end_time" binding:"required"`
+}
+
+type unassignedClass struct {
+	ClassID  uint `json:"class_id"`
+	Sessions int  `json:"sessions"`
+}
+
+// Propose ejecuta el planificador y guarda el resultado como propuesta DRAFT
+func (s *TimetableService) Propose(req TimetableRequest, createdBy uint) (*models.TimetableProposal, error) {
+	term, err := s.termRepo.GetByID(req.TermID)
+	if err != nil {
+		return nil, errors.New("term not found")
+	}
+	if term.ArchivedAt != nil {
+		return nil, errors.New("term is archived")
+	}
+
+	in := solverInput{RoomBusy: map[uint][]busyBlock{}, ProfessorBusy: map[uint][]busyBlock{}}
+
+	requested := map[uint]bool{}
+	for _, cr := range req.Classes {
+		if requested[cr.ClassID] {
+			return nil, fmt.Errorf("class %d listed twice", cr.ClassID)
+		}
+		requested[cr.ClassID] = true
+		class, err := s.classRepo.FindByID(cr.ClassID)
+		if err != nil {
+			return nil, fmt.Errorf("class %d not found", cr.ClassID)
+		}
+		if class.TermID == nil || *class.TermID != req.TermID {
+			return nil, fmt.Errorf("class %d does not belong to term", cr.ClassID)
+		}
+		sc := solverClass{
+			ClassID:           class.ID,
+			ProfessorID:       class.ProfessorID,
+			Staff:             staffIDs(class.Staff),
+			Enrolment:         len(class.Students),
+			Sessions:          cr.SessionsPerWeek,
+			Duration:          cr.DurationMinutes,
+			Resources:         cr.Resources,
+			PreferredRooms:    map[uint]bool{},
+			PreferredWeekdays: map[int]bool{},
+		}
+		if sc.Sessions <= 0 {
+			sc.Sessions = 1
+		}
+		if sc.Duration <= 0 {
+			sc.Duration = 90
+		}
+		for _, id := range cr.PreferredRooms {
+			sc.PreferredRooms[id] = true
+		}
+		for _, d := range cr.PreferredWeekdays {
+			sc.PreferredWeekdays[d] = true
+		}
+		in.Classes = append(in.Classes, sc)
+	}
+
+	for _, slot := range req.Slots {
+		start, err := clockMinutes(slot.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		in.Slots = append(in.Slots, solverSlot{Weekday: slot.Weekday, Start: start})
+	}
+	for _, u := range req.ProfessorUnavailability {
+		start, err := clockMinutes(u.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := clockMinutes(u.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		in.ProfessorBusy[u.ProfessorID] = append(in.ProfessorBusy[u.ProfessorID], busyBlock{Weekday: u.Weekday, Start: start, End: end})
+	}
+
+	rooms, err := s.roomRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rooms {
+		if req.BuildingID != 0 && r.BuildingID != req.BuildingID {
+			continue
+		}
+		in.Rooms = append(in.Rooms, solverRoom{ID: r.ID, Capacity: r.Capacity, Resources: r.Resources})
+	}
+
+	// Las sesiones ya definidas en el período ocupan aulas y a todo el equipo
+	// docente de la clase
+	termClasses, err := s.classRepo.FindAll(req.TermID)
+	if err != nil {
+		return nil, err
+	}
+	peopleOf := map[uint][]uint{}
+	var ids []uint
+	for _, c := range termClasses {
+		peopleOf[c.ID] = []uint{c.ProfessorID}
+		ids = append(ids, c.ID)
+	}
+	staff, err := s.classRepo.FindStaffByClassIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range staff {
+		peopleOf[st.ClassID] = append(peopleOf[st.ClassID], st.UserID)
+	}
+	busy := newBusyIndex(in)
+	existing, err := s.sessionRepo.FindByClassIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, sess := range existing {
+		start, err1 := clockMinutes(sess.StartTime)
+		end, err2 := clockMinutes(sess.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		b := busyBlock{Weekday: sess.Weekday, Start: start, End: end}
+		busy.room(sess.RoomID, b)
+		busy.people(peopleOf[sess.ClassID], b)
+	}
+
+	// Las reservas activas del período (puntuales y exámenes) ocupan su franja
+	// semanal: la sesión se repetiría todas las semanas
+	resvs, err := s.resvRepo.FindActiveBetween(term.StartDate, term.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resvs {
+		for _, b := range weeklyBlocks(r.StartTime, r.EndTime) {
+			busy.room(r.RoomID, b)
+			if r.SignageTokenID == nil {
+				busy.people([]uint{r.UserID}, b)
+			}
+			if r.ClassID != nil {
+				busy.people(peopleOf[*r.ClassID], b)
+			}
+		}
+	}
+
+	res := solveTimetable(in)
+
+	proposal := &models.TimetableProposal{
+		TermID:      req.TermID,
+		CreatedByID: &createdBy,
+		Status:      "DRAFT",
+		Score:       res.Score,
+	}
+	for _, a := range res.Assignments {
+		proposal.Assignments = append(proposal.Assignments, models.TimetableAssignment{
+			ClassID:   a.ClassID,
+			RoomID:    a.RoomID,
+			Weekday:   a.Block.Weekday,
+			StartTime: minutesClock(a.Block.Start),
+			EndTime:   minutesClock(a.Block.End),
+		})
+	}
+	unassigned := []unassignedClass{}
+	for _, cr := range in.Classes {
+		if n := res.Unassigned[cr.ClassID]; n > 0 {
+			unassigned = append(unassigned, unassignedClass{ClassID: cr.ClassID, Sessions: n})
+		}
+	}
+	proposal.Unassigned, _ = json.Marshal(unassigned)
+
+	if err := s.repo.Create(proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func (s *TimetableService) List(termID uint) ([]models.TimetableProposal, error) {
+	return s.repo.List(termID)
+}
+
+func (s *TimetableService) Get(id uint) (*models.TimetableProposal, error) {
+	return s.repo.GetByID(id)
+}
+
+// Commit crea las sesiones (y sus reservas) de una propuesta DRAFT. Si desde
+// que se generó alguna franja quedó ocupada, no se crea nada y se devuelven
+// los conflictos.
+func (s *TimetableService) Commit(id uint) ([]models.Reservation, error) {
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("proposal not found")
+	}
+	if p.Status != "DRAFT" {
+		return nil, errors.New("proposal is not a draft")
+	}
+	sessions := make([]models.ClassSession, 0, len(p.Assignments))
+	for _, a := range p.Assignments {
+		sessions = append(sessions, models.ClassSession{
+			ClassID:   a.ClassID,
+			RoomID:    a.RoomID,
+			Weekday:   a.Weekday,
+			StartTime: a.StartTime,
+			EndTime:   a.EndTime,
+		})
+	}
+	conflicts, err := s.sessions.CreateMany(sessions)
+	if err != nil || len(conflicts) > 0 {
+		return conflicts, err
+	}
+	now := time.Now()
+	return nil, s.repo.SetStatus(id, "COMMITTED", &now)
+}
+
+func (s *TimetableService) Discard(id uint) error {
+	p, err := s.repo.GetByID(id)
+	if err != nil {
+		return errors.New("proposal not found")
+	}
+	if p.Status != "DRAFT" {
+		return errors.New("proposal is not a draft")
+	}
+	return s.repo.SetStatus(id, "DISCARDED", nil)
+}
+
+func staffIDs(staff []models.ClassStaff) []uint {
+	ids := make([]uint, 0, len(staff))
+	for _, st := range staff {
+		ids = append(ids, st.UserID)
+	}
+	return ids
+}
+
+// busyIndex agrega bloques ocupados a la entrada del planificador sin repetir
+// los que ya figuran (una sesión y sus reservas caen en la misma franja)
+type busyIndex struct {
+	in   solverInput
+	seen map[busyKey]bool
+}
+
+type busyKey struct {
+	person bool
+	id     uint
+	block  busyBlock
+}
+
+func newBusyIndex(in solverInput) *busyIndex {
+	return &busyIndex{in: in, seen: map[busyKey]bool{}}
+}
+
+func (x *busyIndex) room(id uint, b busyBlock) {
+	if k := (busyKey{id: id, block: b}); !x.seen[k] {
+		x.seen[k] = true
+		x.in.RoomBusy[id] = append(x.in.RoomBusy[id], b)
+	}
+}
+
+func (x *busyIndex) people(ids []uint, b busyBlock) {
+	for _, id := range ids {
+		if k := (busyKey{person: true, id: id, block: b}); !x.seen[k] {
+			x.seen[k] = true
+			x.in.ProfessorBusy[id] = append(x.in.ProfessorBusy[id], b)
+		}
+	}
+}
+
+// weeklyBlocks traduce un intervalo a los bloques semanales (hora local) que
+// ocupa; si cruza la medianoche se parte en un bloque por día
+func weeklyBlocks(start, end time.Time) []busyBlock {
+	var out []busyBlock
+	start, end = start.In(time.Local), end.In(time.Local)
+	for day := 0; start.Before(end) && day < 7; day++ {
+		midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, time.Local)
+		stop := end
+		if stop.After(midnight) {
+			stop = midnight
+		}
+		from := start.Hour()*60 + start.Minute()
+		to := 24 * 60
+		if stop.Before(midnight) {
+			to = stop.Hour()*60 + stop.Minute()
+			if stop.Second() > 0 || stop.Nanosecond() > 0 {
+				to++
+			}
+		}
+		out = append(out, busyBlock{Weekday: int(start.Weekday()), Start: from, End: to})
+		start = midnight
+	}
+	return out
+}
+
+func clockMinutes(hhmm string) (int, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", hhmm)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func minutesClock(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWeeklyBlocks(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC-3", -3*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	at := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       []busyBlock
+	}{
+		// 2026-03-02 es lunes
+		{"dentro del día", at("2026-03-02 09:00"), at("2026-03-02 10:30"), []busyBlock{{Weekday: 1, Start: 540, End: 630}}},
+		{"termina a medianoche", at("2026-03-02 22:00"), at("2026-03-03 00:00"), []busyBlock{{Weekday: 1, Start: 1320, End: 1440}}},
+		{"cruza la medianoche", at("2026-03-02 23:00"), at("2026-03-03 01:00"), []busyBlock{
+			{Weekday: 1, Start: 1380, End: 1440}, {Weekday: 2, Start: 0, End: 60},
+		}},
+		{"fin con segundos", at("2026-03-02 09:00"), at("2026-03-02 09:59").Add(30 * time.Second), []busyBlock{{Weekday: 1, Start: 540, End: 600}}},
+		// 12:00 UTC del lunes son las 09:00 locales
+		{"instante en UTC", time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC), []busyBlock{{Weekday: 1, Start: 540, End: 600}}},
+		{"intervalo vacío", at("2026-03-02 09:00"), at("2026-03-02 09:00"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weeklyBlocks(tt.start, tt.end); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("weeklyBlocks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
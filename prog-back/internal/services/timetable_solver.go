@@ -0,0 +1,268 @@
+package services
+
+import (
+	"sort"
+	"strings"
+)
+
+// Planificador de horarios en proceso (sin solver externo).
+//
+// Cada sesión semanal a ubicar es una variable cuyo dominio son los pares
+// (franja, aula) que cumplen las restricciones duras: capacidad, recursos del
+// aula, disponibilidad de los docentes y aulas ya ocupadas. La búsqueda es un
+// backtracking con presupuesto de nodos que prueba primero los valores con
+// menor penalización blanda; si no encuentra una solución completa devuelve
+// la mejor parcial (más sesiones ubicadas y, a igualdad, menor penalización).
+
+const solverNodeBudget = 200000
+
+type solverClass struct {
+	ClassID           uint
+	ProfessorID       uint
+	Staff             []uint // co-docentes y ayudantes, que tampoco pueden superponerse
+	Enrolment         int
+	Sessions          int
+	Duration          int // minutos
+	Resources         []string
+	PreferredRooms    map[uint]bool
+	PreferredWeekdays map[int]bool
+}
+
+type solverRoom struct {
+	ID        uint
+	Capacity  int
+	Resources string
+}
+
+type solverSlot struct {
+	Weekday int
+	Start   int // minutos desde medianoche
+}
+
+// busyBlock es un intervalo ocupado de un aula o un profesor
+type busyBlock struct {
+	Weekday    int
+	Start, End int
+}
+
+// people son todos los docentes que dictan la clase
+func (c *solverClass) people() []uint {
+	return append([]uint{c.ProfessorID}, c.Staff...)
+}
+
+// personBusy indica si alguno de los docentes está ocupado en el bloque
+func personBusy(busy map[uint][]busyBlock, people []uint, b busyBlock) bool {
+	for _, id := range people {
+		if conflicts(busy[id], b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b busyBlock) overlaps(o busyBlock) bool {
+	return b.Weekday == o.Weekday && b.Start < o.End && o.Start < b.End
+}
+
+type solverInput struct {
+	Classes       []solverClass
+	Rooms         []solverRoom
+	Slots         []solverSlot
+	RoomBusy      map[uint][]busyBlock // ocupación fija por aula
+	ProfessorBusy map[uint][]busyBlock // indisponibilidad fija por docente
+}
+
+type solverAssignment struct {
+	ClassID uint
+	RoomID  uint
+	Block   busyBlock
+}
+
+type solverResult struct {
+	Assignments []solverAssignment
+	Unassigned  map[uint]int // class_id -> sesiones sin ubicar
+	Score       float64
+}
+
+type solverValue struct {
+	room  solverRoom
+	block busyBlock
+	cost  float64
+}
+
+type solverVar struct {
+	class  *solverClass
+	domain []solverValue
+}
+
+type solverState struct {
+	vars      []solverVar
+	roomBusy  map[uint][]busyBlock
+	profBusy  map[uint][]busyBlock
+	classDays map[uint]map[int]bool
+	current   []*solverValue
+
+	best      []*solverValue
+	bestCount int
+	bestScore float64
+	nodes     int
+}
+
+func solveTimetable(in solverInput) solverResult {
+	st := &solverState{
+		roomBusy:  map[uint][]busyBlock{},
+		profBusy:  map[uint][]busyBlock{},
+		classDays: map[uint]map[int]bool{},
+		bestCount: -1,
+	}
+	for id, blocks := range in.RoomBusy {
+		st.roomBusy[id] = append([]busyBlock(nil), blocks...)
+	}
+	for id, blocks := range in.ProfessorBusy {
+		st.profBusy[id] = append([]busyBlock(nil), blocks...)
+	}
+
+	for i := range in.Classes {
+		cls := &in.Classes[i]
+		domain := buildDomain(cls, in.Rooms, in.Slots, in.RoomBusy, in.ProfessorBusy)
+		for k := 0; k < cls.Sessions; k++ {
+			st.vars = append(st.vars, solverVar{class: cls, domain: domain})
+		}
+	}
+	// Variables más restringidas primero
+	sort.SliceStable(st.vars, func(i, j int) bool {
+		return len(st.vars[i].domain) < len(st.vars[j].domain)
+	})
+	st.current = make([]*solverValue, len(st.vars))
+	st.search(0, 0, 0)
+
+	res := solverResult{Unassigned: map[uint]int{}, Score: st.bestScore}
+	for i, v := range st.best {
+		cls := st.vars[i].class
+		if v == nil {
+			res.Unassigned[cls.ClassID]++
+			continue
+		}
+		res.Assignments = append(res.Assignments, solverAssignment{ClassID: cls.ClassID, RoomID: v.room.ID, Block: v.block})
+	}
+	return res
+}
+
+// buildDomain filtra las combinaciones franja/aula por las restricciones duras
+// que no dependen de otras asignaciones y las ordena por penalización
+func buildDomain(cls *solverClass, rooms []solverRoom, slots []solverSlot, roomBusy, profBusy map[uint][]busyBlock) []solverValue {
+	var out []solverValue
+	for _, slot := range slots {
+		block := busyBlock{Weekday: slot.Weekday, Start: slot.Start, End: slot.Start + cls.Duration}
+		if block.End > 24*60 || personBusy(profBusy, cls.people(), block) {
+			continue
+		}
+		for _, room := range rooms {
+			if room.Capacity < cls.Enrolment || !hasResources(room.Resources, cls.Resources) {
+				continue
+			}
+			if conflicts(roomBusy[room.ID], block) {
+				continue
+			}
+			out = append(out, solverValue{room: room, block: block, cost: softCost(cls, room, block)})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].cost < out[j].cost })
+	return out
+}
+
+func softCost(cls *solverClass, room solverRoom, block busyBlock) float64 {
+	cost := 0.0
+	// Aulas sobredimensionadas desperdician capacidad
+	if room.Capacity > 0 {
+		cost += float64(room.Capacity-cls.Enrolment) / float64(room.Capacity) * 10
+	}
+	if len(cls.PreferredRooms) > 0 && !cls.PreferredRooms[room.ID] {
+		cost += 20
+	}
+	if len(cls.PreferredWeekdays) > 0 && !cls.PreferredWeekdays[block.Weekday] {
+		cost += 10
+	}
+	return cost
+}
+
+func hasResources(roomResources string, required []string) bool {
+	have := strings.ToLower(roomResources)
+	for _, r := range required {
+		if !strings.Contains(have, strings.ToLower(strings.TrimSpace(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+func conflicts(blocks []busyBlock, b busyBlock) bool {
+	for _, o := range blocks {
+		if o.overlaps(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *solverState) search(i, assigned int, score float64) {
+	st.nodes++
+	if i == len(st.vars) {
+		if assigned > st.bestCount || (assigned == st.bestCount && score < st.bestScore) {
+			st.best = append([]*solverValue(nil), st.current...)
+			st.bestCount = assigned
+			st.bestScore = score
+		}
+		return
+	}
+	// Poda: ni ubicando todas las restantes se mejora la mejor solución
+	remaining := len(st.vars) - i
+	if assigned+remaining < st.bestCount ||
+		(assigned+remaining == st.bestCount && score >= st.bestScore) {
+		return
+	}
+
+	v := st.vars[i]
+	cls := v.class
+	for k := range v.domain {
+		if st.nodes > solverNodeBudget {
+			break
+		}
+		val := &v.domain[k]
+		if st.classDays[cls.ClassID][val.block.Weekday] ||
+			conflicts(st.roomBusy[val.room.ID], val.block) ||
+			personBusy(st.profBusy, cls.people(), val.block) {
+			continue
+		}
+		st.place(cls, val)
+		st.current[i] = val
+		st.search(i+1, assigned+1, score+val.cost)
+		st.current[i] = nil
+		st.unplace(cls, val)
+	}
+	// Dejar la sesión sin ubicar como última opción
+	if st.nodes <= solverNodeBudget || st.best == nil {
+		st.search(i+1, assigned, score)
+	}
+}
+
+func (st *solverState) place(cls *solverClass, val *solverValue) {
+	st.roomBusy[val.room.ID] = append(st.roomBusy[val.room.ID], val.block)
+	for _, id := range cls.people() {
+		st.profBusy[id] = append(st.profBusy[id], val.block)
+	}
+	if st.classDays[cls.ClassID] == nil {
+		st.classDays[cls.ClassID] = map[int]bool{}
+	}
+	st.classDays[cls.ClassID][val.block.Weekday] = true
+}
+
+func (st *solverState) unplace(cls *solverClass, val *solverValue) {
+	rb := st.roomBusy[val.room.ID]
+	st.roomBusy[val.room.ID] = rb[:len(rb)-1]
+	for _, id := range cls.people() {
+		pb := st.profBusy[id]
+		st.profBusy[id] = pb[:len(pb)-1]
+	}
+	delete(st.classDays[cls.ClassID], val.block.Weekday)
+}
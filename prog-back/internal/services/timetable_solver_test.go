@@ -0,0 +1,212 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSolveTimetable(t *testing.T) {
+	mon9 := solverSlot{Weekday: 1, Start: 9 * 60}
+	tue9 := solverSlot{Weekday: 2, Start: 9 * 60}
+	mon11 := solverSlot{Weekday: 1, Start: 11 * 60}
+	late := solverSlot{Weekday: 1, Start: 23 * 60}
+
+	small := solverRoom{ID: 1, Capacity: 20, Resources: "pizarra"}
+	big := solverRoom{ID: 2, Capacity: 40, Resources: "Proyector, pizarra"}
+
+	tests := []struct {
+		name       string
+		in         solverInput
+		want       []solverAssignment
+		unassigned map[uint]int
+	}{
+		{
+			name: "aula con capacidad suficiente",
+			in: solverInput{
+				Classes: []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 30, Sessions: 1, Duration: 60}},
+				Rooms:   []solverRoom{small, big},
+				Slots:   []solverSlot{mon9},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 2, Block: busyBlock{Weekday: 1, Start: 540, End: 600}}},
+		},
+		{
+			name: "recursos requeridos sin distinguir mayúsculas",
+			in: solverInput{
+				Classes: []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 10, Sessions: 1, Duration: 60, Resources: []string{" proyector"}}},
+				Rooms:   []solverRoom{small, big},
+				Slots:   []solverSlot{mon9},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 2, Block: busyBlock{Weekday: 1, Start: 540, End: 600}}},
+		},
+		{
+			name: "indisponibilidad del profesor",
+			in: solverInput{
+				Classes:       []solverClass{{ClassID: 1, ProfessorID: 7, Enrolment: 10, Sessions: 1, Duration: 60}},
+				Rooms:         []solverRoom{small},
+				Slots:         []solverSlot{mon9, tue9},
+				ProfessorBusy: map[uint][]busyBlock{7: {{Weekday: 1, Start: 8 * 60, End: 10 * 60}}},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 2, Start: 540, End: 600}}},
+		},
+		{
+			name: "aula ya ocupada",
+			in: solverInput{
+				Classes:  []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 10, Sessions: 1, Duration: 60}},
+				Rooms:    []solverRoom{small},
+				Slots:    []solverSlot{mon9, mon11},
+				RoomBusy: map[uint][]busyBlock{1: {{Weekday: 1, Start: 9*60 + 30, End: 10 * 60}}},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 1, Start: 660, End: 720}}},
+		},
+		{
+			name: "una sola sesión por día para la misma clase",
+			in: solverInput{
+				Classes: []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 10, Sessions: 2, Duration: 60}},
+				Rooms:   []solverRoom{small},
+				Slots:   []solverSlot{mon9, mon11},
+			},
+			want:       []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 1, Start: 540, End: 600}}},
+			unassigned: map[uint]int{1: 1},
+		},
+		{
+			name: "el profesor no da dos clases a la vez",
+			in: solverInput{
+				Classes: []solverClass{
+					{ClassID: 1, ProfessorID: 3, Enrolment: 10, Sessions: 1, Duration: 60},
+					{ClassID: 2, ProfessorID: 3, Enrolment: 10, Sessions: 1, Duration: 60},
+				},
+				Rooms: []solverRoom{small, big},
+				Slots: []solverSlot{mon9},
+			},
+			want:       []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 1, Start: 540, End: 600}}},
+			unassigned: map[uint]int{2: 1},
+		},
+		{
+			name: "el ayudante no da dos clases a la vez",
+			in: solverInput{
+				Classes: []solverClass{
+					{ClassID: 1, ProfessorID: 3, Staff: []uint{9}, Enrolment: 10, Sessions: 1, Duration: 60},
+					{ClassID: 2, ProfessorID: 4, Staff: []uint{9}, Enrolment: 10, Sessions: 1, Duration: 60},
+				},
+				Rooms: []solverRoom{small, big},
+				Slots: []solverSlot{mon9},
+			},
+			want:       []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 1, Start: 540, End: 600}}},
+			unassigned: map[uint]int{2: 1},
+		},
+		{
+			name: "indisponibilidad del co-docente",
+			in: solverInput{
+				Classes:       []solverClass{{ClassID: 1, ProfessorID: 3, Staff: []uint{8}, Enrolment: 10, Sessions: 1, Duration: 60}},
+				Rooms:         []solverRoom{small},
+				Slots:         []solverSlot{mon9, tue9},
+				ProfessorBusy: map[uint][]busyBlock{8: {{Weekday: 1, Start: 9 * 60, End: 10 * 60}}},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 2, Start: 540, End: 600}}},
+		},
+		{
+			name: "la sesión no puede pasar de medianoche",
+			in: solverInput{
+				Classes: []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 10, Sessions: 1, Duration: 120}},
+				Rooms:   []solverRoom{small},
+				Slots:   []solverSlot{late},
+			},
+			unassigned: map[uint]int{1: 1},
+		},
+		{
+			name: "prefiere el día indicado",
+			in: solverInput{
+				Classes: []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 10, Sessions: 1, Duration: 60,
+					PreferredWeekdays: map[int]bool{2: true}}},
+				Rooms: []solverRoom{small},
+				Slots: []solverSlot{mon9, tue9},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 1, Block: busyBlock{Weekday: 2, Start: 540, End: 600}}},
+		},
+		{
+			name: "prefiere el aula indicada aunque sobre capacidad",
+			in: solverInput{
+				Classes: []solverClass{{ClassID: 1, ProfessorID: 1, Enrolment: 10, Sessions: 1, Duration: 60,
+					PreferredRooms: map[uint]bool{2: true}}},
+				Rooms: []solverRoom{small, big},
+				Slots: []solverSlot{mon9},
+			},
+			want: []solverAssignment{{ClassID: 1, RoomID: 2, Block: busyBlock{Weekday: 1, Start: 540, End: 600}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := solveTimetable(tt.in)
+			if !reflect.DeepEqual(res.Assignments, tt.want) {
+				t.Errorf("assignments = %+v, want %+v", res.Assignments, tt.want)
+			}
+			want := tt.unassigned
+			if want == nil {
+				want = map[uint]int{}
+			}
+			if !reflect.DeepEqual(res.Unassigned, want) {
+				t.Errorf("unassigned = %v, want %v", res.Unassigned, want)
+			}
+		})
+	}
+}
+
+func TestSolveTimetableRespectsHardConstraints(t *testing.T) {
+	in := solverInput{
+		Rooms: []solverRoom{{ID: 1, Capacity: 30}, {ID: 2, Capacity: 30}},
+		RoomBusy: map[uint][]busyBlock{
+			1: {{Weekday: 3, Start: 8 * 60, End: 12 * 60}},
+		},
+		ProfessorBusy: map[uint][]busyBlock{
+			2: {{Weekday: 1, Start: 0, End: 24 * 60}},
+		},
+	}
+	for day := 1; day <= 5; day++ {
+		for h := 8; h < 12; h++ {
+			in.Slots = append(in.Slots, solverSlot{Weekday: day, Start: h * 60})
+		}
+	}
+	for id := uint(1); id <= 4; id++ {
+		in.Classes = append(in.Classes, solverClass{
+			ClassID: id, ProfessorID: id%3 + 1, Enrolment: 25, Sessions: 3, Duration: 90,
+		})
+	}
+
+	res := solveTimetable(in)
+	if len(res.Unassigned) != 0 {
+		t.Fatalf("unassigned = %v, want none", res.Unassigned)
+	}
+
+	profOf := map[uint]uint{}
+	for _, c := range in.Classes {
+		profOf[c.ClassID] = c.ProfessorID
+	}
+	days := map[uint]map[int]bool{}
+	for i, a := range res.Assignments {
+		if conflicts(in.RoomBusy[a.RoomID], a.Block) {
+			t.Errorf("class %d placed in busy room %d at %+v", a.ClassID, a.RoomID, a.Block)
+		}
+		if conflicts(in.ProfessorBusy[profOf[a.ClassID]], a.Block) {
+			t.Errorf("class %d placed while professor is unavailable at %+v", a.ClassID, a.Block)
+		}
+		if days[a.ClassID] == nil {
+			days[a.ClassID] = map[int]bool{}
+		}
+		if days[a.ClassID][a.Block.Weekday] {
+			t.Errorf("class %d has two sessions on weekday %d", a.ClassID, a.Block.Weekday)
+		}
+		days[a.ClassID][a.Block.Weekday] = true
+		for _, b := range res.Assignments[i+1:] {
+			if !a.Block.overlaps(b.Block) {
+				continue
+			}
+			if a.RoomID == b.RoomID {
+				t.Errorf("room %d double booked: %+v and %+v", a.RoomID, a, b)
+			}
+			if profOf[a.ClassID] == profOf[b.ClassID] {
+				t.Errorf("professor %d double booked: %+v and %+v", profOf[a.ClassID], a, b)
+			}
+		}
+	}
+}
@@ -1,17 +1,25 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+
 	"programcion-backend/internal/models"
 	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
 )
 
 type UserService struct {
-	repo *repositories.UserRepository
+	repo      *repositories.UserRepository
+	classRepo *repositories.ClassRepository
+	classes   *ClassService
 }
 
-func NewUserService() *UserService {
+func NewUserService(cfg *config.Config) *UserService {
 	return &UserService{
-		repo: repositories.NewUserRepository(),
+		repo:      repositories.NewUserRepository(),
+		classRepo: repositories.NewClassRepository(),
+		classes:   NewClassService(cfg),
 	}
 }
 
@@ -22,3 +30,98 @@ func (s *UserService) GetUsersWithFilters(role string, isConfirmed *bool) ([]mod
 func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 	return s.repo.GetByID(id)
 }
+
+// DeleteUser borra el usuario de forma lógica. Un profesor con clases vigentes
+// no puede borrarse hasta reasignarlas o borrarlas.
+func (s *UserService) DeleteUser(id, actorID uint) error {
+	if id == actorID {
+		return errors.New("cannot delete your own account")
+	}
+	if _, err := s.repo.GetByID(id); err != nil {
+		return errors.New("user not found")
+	}
+	owned, err := s.classRepo.CountByProfessor(id)
+	if err != nil {
+		return err
+	}
+	if owned > 0 {
+		return fmt.Errorf("user owns %d class(es); delete or reassign them first", owned)
+	}
+	// Cupo de cada clase donde libera un lugar, para promover su lista de espera
+	enrolled, err := s.classRepo.FindClassesByStudentID(id, 0)
+	if err != nil {
+		return err
+	}
+	capacities := make(map[uint]int, len(enrolled))
+	for _, class := range enrolled {
+		if capacities[class.ID], err = s.classes.effectiveCapacity(class.ID); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(id, capacities)
+}
+
+func (s *UserService) ListDeletedUsers() ([]models.User, error) {
+	return s.repo.FindDeleted()
+}
+
+// RestoreUser recupera un usuario borrado y lo vuelve a inscribir en sus
+// clases vigentes respetando el cupo (puede quedar en lista de espera). Todo
+// ocurre en una transacción; los avisos salen después de confirmarla.
+func (s *UserService) RestoreUser(id uint) error {
+	if _, err := s.repo.FindDeletedByID(id); err != nil {
+		return errors.New("deleted user not found")
+	}
+	classIDs, err := s.repo.ArchivedEnrollments(id)
+	if err != nil {
+		return err
+	}
+	capacities := make(map[uint]int, len(classIDs))
+	for _, classID := range classIDs {
+		if capacities[classID], err = s.classes.effectiveCapacity(classID); err != nil {
+			return err
+		}
+	}
+	statuses, err := s.repo.Restore(id, capacities)
+	if err != nil {
+		return err
+	}
+	for classID, status := range statuses {
+		event := EventClassEnrolled
+		if status == "WAITLISTED" {
+			event = EventClassWaitlisted
+		}
+		s.classes.notifyEnrollment(event, classID, id)
+	}
+	return nil
+}
+
+// PurgeUser elimina definitivamente un usuario borrado. Se rechaza si aún hay
+// reservas, clases o tokens de cartelería vigentes que lo referencian.
+func (s *UserService) PurgeUser(id uint) error {
+	if _, err := s.repo.FindDeletedByID(id); err != nil {
+		return errors.New("deleted user not found")
+	}
+	resvs, err := s.repo.CountReservations(id)
+	if err != nil {
+		return err
+	}
+	if resvs > 0 {
+		return fmt.Errorf("user still has %d reservation(s)", resvs)
+	}
+	classes, err := s.classRepo.CountByProfessorUnscoped(id)
+	if err != nil {
+		return err
+	}
+	if classes > 0 {
+		return fmt.Errorf("user still owns %d class(es), including deleted ones", classes)
+	}
+	tokens, err := s.repo.CountActiveSignageTokens(id)
+	if err != nil {
+		return err
+	}
+	if tokens > 0 {
+		return fmt.Errorf("user still owns %d active signage token(s)", tokens)
+	}
+	return s.repo.Purge(id)
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"programcion-backend/internal/models"
+	"programcion-backend/internal/repositories"
+	"programcion-backend/pkg/config"
+	"programcion-backend/pkg/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Eventos de dominio que se pueden suscribir por webhook (los publica el relay del outbox)
+var WebhookEvents = []string{
+	models.EventReservationCreated,
+	models.EventReservationCancelled,
+	models.EventReservationCheckedIn,
+	models.EventReservationNoShow,
+	models.EventReservationExpired,
+	models.EventRoomUpdated,
+	models.EventClassCreated,
+	models.EventClassUpdated,
+	models.EventClassDeleted,
+	models.EventClassRestored,
+	models.EventEnrollmentChanged,
+	models.EventUserCreated,
+	models.EventUserUpdated,
+	models.EventUserDeleted,
+	models.EventUserRestored,
+}
+
+// Reintentos: 1m, 2m, 4m... hasta maxWebhookAttempts por entrega
+const (
+	maxWebhookAttempts  = 8
+	webhookBaseBackoff  = time.Minute
+	webhookLease        = 2 * time.Minute
+	webhookBatchSize    = 20
+	webhookTimeout      = 10 * time.Second
+	webhookDeliveryPage = 100
+)
+
+type WebhookService struct {
+	repo         *repositories.WebhookRepository
+	client       *http.Client
+	disableAfter int
+}
+
+// NewWebhookService deshabilita un endpoint tras cfg.Webhooks.DisableAfter
+// fallos consecutivos
+func NewWebhookService(cfg *config.Config) *WebhookService {
+	return &WebhookService{
+		repo:         repositories.NewWebhookRepository(),
+		client:       &http.Client{Timeout: webhookTimeout},
+		disableAfter: cfg.Webhooks.DisableAfter,
+	}
+}
+
+// webhookPayload es el cuerpo que recibe el endpoint
+type webhookPayload struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Publish encola el evento del outbox para cada suscripción activa
+// interesada. El id del evento es el del outbox, estable entre reintentos del
+// relay: las suscripciones que ya tienen la entrega encolada se omiten.
+func (s *WebhookService) Publish(e *models.OutboxEvent) error {
+	eventID := strconv.FormatUint(uint64(e.ID), 10)
+	subs, err := s.repo.FindActive()
+	if err != nil {
+		return err
+	}
+	queued, err := s.repo.SubscriptionsWithEvent(eventID)
+	if err != nil {
+		return err
+	}
+	skip := make(map[uint]bool, len(queued))
+	for _, id := range queued {
+		skip[id] = true
+	}
+	var targets []models.WebhookSubscription
+	for _, sub := range subs {
+		if !skip[sub.ID] && subscribesTo(sub.Events, e.Event) {
+			targets = append(targets, sub)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	now := time.Now()
+	body, err := json.Marshal(webhookPayload{ID: eventID, Event: e.Event, CreatedAt: e.CreatedAt, Data: e.Payload})
+	if err != nil {
+		return err
+	}
+	deliveries := make([]models.WebhookDelivery, 0, len(targets))
+	for _, sub := range targets {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			Event:          e.Event,
+			EventID:        eventID,
+			Payload:        body,
+			Status:         "PENDING",
+			NextAttemptAt:  now,
+		})
+	}
+	return s.repo.CreateDeliveries(deliveries)
+}
+
+func subscribesTo(events, event string) bool {
+	for _, e := range strings.Split(events, ",") {
+		if e = strings.TrimSpace(e); e == "*" || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign calcula la firma enviada en X-Webhook-Signature:
+// sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DispatchDue envía las entregas pendientes y devuelve cuántas tuvieron éxito
+func (s *WebhookService) DispatchDue() (int, error) {
+	list, err := s.repo.ClaimDueDeliveries(webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+	subs := map[uint]*models.WebhookSubscription{}
+	ok := 0
+	for i := range list {
+		d := &list[i]
+		sub, found := subs[d.SubscriptionID]
+		if !found {
+			sub, err = s.repo.FindByID(d.SubscriptionID)
+			if err != nil {
+				// Error transitorio: la entrega se reintenta al vencer el lease
+				log.WithError(err).WithField("delivery_id", d.ID).Error("Error leyendo suscripción de webhook")
+				continue
+			}
+			subs[d.SubscriptionID] = sub
+		}
+		// La suscripción se borró o deshabilitó después de reclamar la entrega
+		if sub == nil || !sub.Active {
+			reason := "subscription deleted"
+			if sub != nil {
+				reason = "subscription disabled"
+			}
+			if err := s.repo.MarkDead(d, reason); err != nil {
+				log.WithError(err).WithField("delivery_id", d.ID).Error("Error descartando entrega de webhook")
+			}
+			continue
+		}
+		code, sendErr := s.send(sub, d)
+		if sendErr == nil {
+			if err := s.repo.MarkSucceeded(d, code, time.Now()); err != nil {
+				log.WithError(err).WithField("delivery_id", d.ID).Error("Error registrando entrega de webhook")
+			}
+			ok++
+			continue
+		}
+		var next *time.Time
+		if d.Attempts+1 < maxWebhookAttempts {
+			t := time.Now().Add(webhookBaseBackoff << d.Attempts)
+			next = &t
+		}
+		disabled, err := s.repo.MarkFailed(d, code, sendErr.Error(), next, s.disableAfter)
+		if err != nil {
+			log.WithError(err).WithField("delivery_id", d.ID).Error("Error registrando fallo de webhook")
+		}
+		if disabled {
+			log.WithField("subscription_id", sub.ID).Warn("Webhook deshabilitado por fallos consecutivos")
+		}
+	}
+	return ok, nil
+}
+
+func (s *WebhookService) send(sub *models.WebhookSubscription, d *models.WebhookDelivery) (int, error) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "programcion-backend-webhooks")
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-Id", d.EventID)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(d.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", Sign(sub.Secret, ts, d.Payload))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Administración de suscripciones
+
+// WebhookRequest crea o modifica una suscripción
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+func validateWebhook(rawURL string, events []string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("url must be an absolute http(s) URL")
+	}
+	if len(events) == 0 {
+		return "", errors.New("at least one event is required")
+	}
+	for _, e := range events {
+		if e == "*" {
+			continue
+		}
+		known := false
+		for _, k := range WebhookEvents {
+			known = known || k == e
+		}
+		if !known {
+			return "", errors.New("unknown event: " + e)
+		}
+	}
+	return strings.Join(events, ","), nil
+}
+
+// Create registra la suscripción y devuelve el secreto HMAC (sólo esta vez)
+func (s *WebhookService) Create(req WebhookRequest, createdByID uint) (*models.WebhookSubscription, string, error) {
+	events, err := validateWebhook(req.URL, req.Events)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := utils.GenerateToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	sub := &models.WebhookSubscription{URL: req.URL, Secret: secret, Events: events, Active: true, CreatedByID: &createdByID}
+	if err := s.repo.Create(sub); err != nil {
+		return nil, "", err
+	}
+	return sub, secret, nil
+}
+
+func (s *WebhookService) List() ([]models.WebhookSubscription, error) {
+	return s.repo.List()
+}
+
+func (s *WebhookService) Get(id uint) (*models.WebhookSubscription, error) {
+	return s.repo.GetByID(id)
+}
+
+// Update modifica URL, eventos o estado. Reactivar limpia el contador de fallos.
+func (s *WebhookService) Update(id uint, req WebhookRequest) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("webhook not found")
+	}
+	rawURL, events := sub.URL, strings.Split(sub.Events, ",")
+	if req.URL != "" {
+		rawURL = req.URL
+	}
+	if req.Events != nil {
+		events = req.Events
+	}
+	joined, err := validateWebhook(rawURL, events)
+	if err != nil {
+		return nil, err
+	}
+	sub.URL, sub.Events = rawURL, joined
+	if req.Active != nil {
+		if *req.Active && !sub.Active {
+			sub.ConsecutiveFailures = 0
+			sub.DisabledAt = nil
+		}
+		sub.Active = *req.Active
+	}
+	if err := s.repo.Update(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) Delete(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *WebhookService) RotateSecret(id uint) (string, error) {
+	sub, err := s.repo.GetByID(id)
+	if err != nil {
+		return "", errors.New("webhook not found")
+	}
+	secret, err := utils.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+	sub.Secret = secret
+	return secret, s.repo.Update(sub)
+}
+
+func (s *WebhookService) Deliveries(id uint, status string) ([]models.WebhookDelivery, error) {
+	return s.repo.ListDeliveries(id, status, webhookDeliveryPage)
+}
+
+// Redeliver encola una nueva entrega con el mismo evento; el intento original
+// queda en el historial
+func (s *WebhookService) Redeliver(subscriptionID, deliveryID uint) (*models.WebhookDelivery, error) {
+	d, err := s.repo.GetDelivery(deliveryID)
+	if err != nil || d.SubscriptionID != subscriptionID {
+		return nil, errors.New("delivery not found")
+	}
+	again := []models.WebhookDelivery{{
+		SubscriptionID: d.SubscriptionID,
+		Event:          d.Event,
+		EventID:        d.EventID,
+		Payload:        d.Payload,
+		Status:         "PENDING",
+		NextAttemptAt:  time.Now(),
+	}}
+	if err := s.repo.CreateDeliveries(again); err != nil {
+		return nil, err
+	}
+	return &again[0], nil
+}
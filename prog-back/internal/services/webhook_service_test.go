@@ -0,0 +1,76 @@
+package services
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{
+			name:      "payload JSON",
+			secret:    "s3cr3t",
+			timestamp: "1700000000",
+			body:      `{"event":"ping"}`,
+			want:      "sha256=84f7f3b2362bd20c7ae13752f6307690e83aee6e4b34eddcfce2e9184a16627f",
+		},
+		{
+			name:      "cuerpo vacío",
+			secret:    "key",
+			timestamp: "0",
+			body:      "",
+			want:      "sha256=85841b4efc3cd7776c3c8f9b7cca9e281c550e5d19889d78e9e669c6337f000d",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignCoversEveryInput(t *testing.T) {
+	base := Sign("s3cr3t", "1700000000", []byte(`{"event":"ping"}`))
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+	}{
+		{"otro secreto", "s3cr3T", "1700000000", `{"event":"ping"}`},
+		{"otro timestamp", "s3cr3t", "1700000001", `{"event":"ping"}`},
+		{"cuerpo alterado", "s3cr3t", "1700000000", `{"event":"pong"}`},
+		// El separador evita que se puedan mover bytes entre timestamp y cuerpo
+		{"separador desplazado", "s3cr3t", "170000000", `0.{"event":"ping"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Sign(tt.secret, tt.timestamp, []byte(tt.body)) == base {
+				t.Errorf("signature did not change")
+			}
+		})
+	}
+}
+
+func TestSubscribesTo(t *testing.T) {
+	tests := []struct {
+		events string
+		event  string
+		want   bool
+	}{
+		{"*", "reservation.created", true},
+		{"reservation.created, reservation.cancelled", "reservation.cancelled", true},
+		{"reservation.created", "reservation.cancelled", false},
+		{"", "reservation.created", false},
+	}
+	for _, tt := range tests {
+		if got := subscribesTo(tt.events, tt.event); got != tt.want {
+			t.Errorf("subscribesTo(%q, %q) = %v, want %v", tt.events, tt.event, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"programcion-backend/internal/jobs"
+	"programcion-backend/internal/middleware"
 	"programcion-backend/internal/routes"
-	"programcion-backend/internal/seeder"
 	"programcion-backend/pkg/config"
 	"programcion-backend/pkg/db"
 
@@ -11,39 +16,70 @@ import (
 )
 
 func main() {
-	// Cargar configuración y variables de entorno
-	config.LoadConfig()
+	// Cargar configuración: valores por defecto, CONFIG_FILE, .env y entorno
+	cfg, cfgErr := config.Load()
 
-	// Inicializar la base de datos (Postgres + GORM)
-	if err := db.InitDB(); err != nil {
-		log.WithError(err).Fatal("No se pudo inicializar la base de datos")
+	// Subcomandos: migrate up|down|status|create, seed <perfil>, config print.
+	// config print muestra la configuración aunque sea inválida y migrate
+	// create no la usa (sólo escribe archivos en el repositorio).
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		exitOnError(runConfig(cfg, cfgErr, os.Args[2:]))
+		return
+	}
+	if len(os.Args) > 2 && os.Args[1] == "migrate" && os.Args[2] == "create" {
+		exitOnError(runMigrate(cfg, os.Args[2:]))
+		return
+	}
+	if cfgErr != nil {
+		exitOnError(cfgErr)
+	}
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "migrate":
+			err = runMigrate(cfg, os.Args[2:])
+		case "seed":
+			err = runSeed(cfg, os.Args[2:])
+		default:
+			err = fmt.Errorf("unknown command %q (available: migrate, seed, config)", os.Args[1])
+		}
+		exitOnError(err)
+		return
 	}
 
-	// Ejecutar seeder para crear admin inicial si no existe
-	if err := seeder.SeedAdmin(); err != nil {
-		log.WithError(err).Fatal("Seeder falló")
+	// Inicializar la base de datos (Postgres + GORM) y comprobar el esquema
+	if err := db.InitDB(cfg.Database); err != nil {
+		log.WithError(err).Fatal("No se pudo inicializar la base de datos")
 	}
+	ensureSchema(cfg)
+
+	// Tareas en segundo plano
+	jobs.StartNoShowReleaser(cfg)
+	jobs.StartHoldSweeper(cfg)
+	jobs.StartTermArchiver()
+	jobs.StartNotificationDispatcher(cfg)
+	jobs.StartReminderScheduler(cfg)
+	jobs.StartWebhookDispatcher(cfg)
+	jobs.StartOutboxRelay(cfg)
 
 	r := gin.Default()
 
-	// CORS - permitir cookies desde frontend en desarrollo
-	r.Use(func() gin.HandlerFunc {
-		return func(c *gin.Context) {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			if c.Request.Method == "OPTIONS" {
-				c.AbortWithStatus(200)
-				return
-			}
-			c.Next()
-		}
-	}())
+	// CORS - permitir cookies desde el frontend
+	r.Use(middleware.CORS(cfg.Server.CORSOrigins))
 
 	// Configurar rutas
-	routes.SetupRoutes(r)
+	routes.SetupRoutes(r, cfg)
+
+	addr := ":" + strconv.Itoa(cfg.Server.Port)
+	log.Infof("Servidor iniciado en %s", addr)
+	if err := r.Run(addr); err != nil {
+		log.WithError(err).Fatal("El servidor se detuvo")
+	}
+}
 
-	log.Info("Servidor iniciado en :8080")
-	r.Run(":8080")
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
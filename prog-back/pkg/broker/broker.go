@@ -0,0 +1,112 @@
+// Package broker define una interfaz mínima de mensajería compatible con
+// NATS (subject) y Kafka (topic + key) y una implementación en memoria
+package broker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Message es un mensaje publicado en un subject. Key agrupa los mensajes que
+// deben conservar el orden (en Kafka determina la partición).
+type Message struct {
+	Subject string
+	Key     string
+	Data    []byte
+	Headers map[string]string
+}
+
+type Handler func(msg Message)
+
+// Broker es lo que necesita el relay del outbox. Un adaptador de NATS o Kafka
+// sólo tiene que implementar estos tres métodos.
+type Broker interface {
+	Publish(ctx context.Context, msg Message) error
+	// Subscribe admite comodines al estilo NATS: "*" reemplaza un segmento y
+	// ">" al final reemplaza uno o más segmentos
+	Subscribe(pattern string, h Handler) (unsubscribe func(), err error)
+	Close() error
+}
+
+var ErrClosed = errors.New("broker closed")
+
+// MemoryBroker entrega los mensajes de forma sincrónica a los suscriptores del
+// mismo proceso, en el orden de publicación
+type MemoryBroker struct {
+	mu     sync.RWMutex
+	subs   map[int]subscription
+	nextID int
+	closed bool
+}
+
+type subscription struct {
+	pattern string
+	handler Handler
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subs: map[int]subscription{}}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	var handlers []Handler
+	for id := 0; id < b.nextID; id++ {
+		if s, ok := b.subs[id]; ok && Match(s.pattern, msg.Subject) {
+			handlers = append(handlers, s.handler)
+		}
+	}
+	b.mu.RUnlock()
+	for _, h := range handlers {
+		h(msg)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(pattern string, h Handler) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = subscription{pattern: pattern, handler: h}
+	return func() {
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+	}, nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.subs = map[int]subscription{}
+	return nil
+}
+
+// Match indica si el subject cumple el patrón con comodines al estilo NATS
+func Match(pattern, subject string) bool {
+	p := strings.Split(pattern, ".")
+	s := strings.Split(subject, ".")
+	for i, tok := range p {
+		if tok == ">" {
+			return i == len(p)-1 && len(s) > i
+		}
+		if i >= len(s) || (tok != "*" && tok != s[i]) {
+			return false
+		}
+	}
+	return len(p) == len(s)
+}
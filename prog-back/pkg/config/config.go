@@ -1,13 +1,274 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/joho/godotenv"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
-func LoadConfig() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Warn("No se pudo cargar el archivo .env, usando variables de entorno del sistema")
+// Config es la configuración tipada de la aplicación. Cada campo se toma, de
+// menor a mayor prioridad, de su valor por defecto (tag default), del YAML de
+// CONFIG_FILE (tag yaml), del archivo .env y de las variables de entorno (tag
+// env). Los campos con secret:"true" se ocultan al imprimirla.
+type Config struct {
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Auth          AuthConfig          `yaml:"auth"`
+	Admin         AdminConfig         `yaml:"admin"`
+	Seed          SeedConfig          `yaml:"seed"`
+	Reservations  ReservationsConfig  `yaml:"reservations"`
+	SMTP          SMTPConfig          `yaml:"smtp"`
+	Reminders     RemindersConfig     `yaml:"reminders"`
+	Webhooks      WebhooksConfig      `yaml:"webhooks"`
+	Events        EventsConfig        `yaml:"events"`
+	PublicListing PublicListingConfig `yaml:"public_listing"`
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port" env:"PORT" default:"8080"`
+	// Orígenes admitidos por CORS (con credenciales, así que "*" no vale)
+	CORSOrigins []string `yaml:"cors_origins" env:"CORS_ORIGINS" default:"http://localhost:3000"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port     int    `yaml:"port" env:"DB_PORT" default:"5432"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+	// Aplicar las migraciones pendientes al arrancar el servidor
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"DB_MIGRATE_ON_START" default:"false"`
+}
+
+// DSN arma la cadena de conexión de Postgres
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+type AuthConfig struct {
+	// Firma los JWT y los códigos de asistencia
+	JWTSecret string `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+}
+
+// AdminConfig es el administrador que crean los perfiles de seed
+type AdminConfig struct {
+	Email    string `yaml:"email" env:"ADMIN_EMAIL"`
+	Password string `yaml:"password" env:"ADMIN_PASSWORD" secret:"true"`
+	Name     string `yaml:"name" env:"ADMIN_NAME" default:"Admin Inicial"`
+}
+
+type SeedConfig struct {
+	// Permite los perfiles con datos ficticios (demo, load-test)
+	AllowDemo bool `yaml:"allow_demo" env:"SEED_ALLOW_DEMO" default:"false"`
+}
+
+// Modos de PERSON_CONFLICT_MODE
+const (
+	ConflictModeError = "error" // rechaza la reserva (por defecto)
+	ConflictModeWarn  = "warn"  // la acepta e informa los choques en warnings
+	ConflictModeOff   = "off"
+)
+
+type ReservationsConfig struct {
+	// Minutos tras el inicio para liberar reservas sin check-in
+	NoShowGraceMinutes     int    `yaml:"no_show_grace_minutes" env:"NO_SHOW_GRACE_MINUTES" default:"15"`
+	PersonConflictMode     string `yaml:"person_conflict_mode" env:"PERSON_CONFLICT_MODE" default:"error"`
+	PersonConflictStudents bool   `yaml:"person_conflict_students" env:"PERSON_CONFLICT_STUDENTS" default:"false"`
+}
+
+// SMTPConfig configura el correo saliente; sin Host sólo hay bandeja in-app
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" default:"587"`
+	User     string `yaml:"user" env:"SMTP_USER"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+}
+
+type RemindersConfig struct {
+	// Anticipación de los recordatorios previos a cada reserva; Load los
+	// deja ordenados de mayor a menor
+	Offsets []time.Duration `yaml:"offsets" env:"REMINDER_OFFSETS" default:"24h,1h"`
+	// Hora local del resumen diario (-1 = sin resumen)
+	DigestHour int `yaml:"digest_hour" env:"DIGEST_HOUR" default:"7"`
+}
+
+type WebhooksConfig struct {
+	// Fallos consecutivos tras los cuales se deshabilita un endpoint
+	DisableAfter int `yaml:"disable_after" env:"WEBHOOK_DISABLE_AFTER" default:"20"`
+}
+
+type EventsConfig struct {
+	// Broker del outbox: memory | none
+	Broker string `yaml:"broker" env:"EVENT_BROKER" default:"memory"`
+}
+
+// Campos opcionales de /api/public/reservations. Los datos personales
+// (user_*) sólo se muestran si se habilitan explícitamente.
+var PublicReservationFields = []string{"purpose", "class", "attendees", "user_name", "user_email"}
+
+type PublicListingConfig struct {
+	ReservationFields []string `yaml:"reservation_fields" env:"PUBLIC_RESERVATION_FIELDS" default:"purpose,class"`
+}
+
+// ValidationError reúne todos los valores inválidos de la configuración
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load arma la configuración efectiva y la valida. Si sólo falla la
+// validación devuelve también la configuración (para `config print`) junto a
+// un *ValidationError.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading .env: %w", err)
+	} else if err != nil {
+		log.Debug("Sin archivo .env, usando variables de entorno del sistema")
+	}
+
+	cfg := &Config{}
+	var problems []string
+	if err := applyDefaults(cfg); err != nil {
+		return nil, err
+	}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading CONFIG_FILE: %w", err)
+		}
+		dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			var typeErr *yaml.TypeError
+			if !errors.As(err, &typeErr) {
+				return nil, fmt.Errorf("parsing %s: %w", path, err)
+			}
+			for _, e := range typeErr.Errors {
+				problems = append(problems, path+": "+e)
+			}
+		}
+	}
+	problems = append(problems, applyEnv(cfg)...)
+	cfg.normalize()
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// normalize ordena los valores cuyo orden no debe depender de cómo se escribieron
+func (c *Config) normalize() {
+	// Los recordatorios se evalúan de la mayor anticipación a la menor
+	sort.Slice(c.Reminders.Offsets, func(i, j int) bool { return c.Reminders.Offsets[i] > c.Reminders.Offsets[j] })
+}
+
+func (c *Config) validate() []string {
+	var p []string
+	add := func(format string, args ...any) { p = append(p, fmt.Sprintf(format, args...)) }
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("PORT: must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if len(c.Server.CORSOrigins) == 0 {
+		add("CORS_ORIGINS: at least one origin is required")
+	}
+	for _, o := range c.Server.CORSOrigins {
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			add("CORS_ORIGINS: %q is not an origin like https://host[:port]", o)
+		}
+	}
+
+	if c.Database.Host == "" {
+		add("DB_HOST: required")
+	}
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		add("DB_PORT: must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	if c.Database.User == "" {
+		add("DB_USER: required")
+	}
+	if c.Database.Name == "" {
+		add("DB_NAME: required")
+	}
+	if !oneOf(c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full") {
+		add("DB_SSLMODE: unknown mode %q", c.Database.SSLMode)
+	}
+
+	if len(c.Auth.JWTSecret) < 16 {
+		add("JWT_SECRET: required, at least 16 characters")
+	}
+
+	if (c.Admin.Email == "") != (c.Admin.Password == "") {
+		add("ADMIN_EMAIL/ADMIN_PASSWORD: set both or neither")
+	}
+	if c.Admin.Email != "" && !strings.Contains(c.Admin.Email, "@") {
+		add("ADMIN_EMAIL: %q is not an email address", c.Admin.Email)
+	}
+
+	if c.Reservations.NoShowGraceMinutes <= 0 {
+		add("NO_SHOW_GRACE_MINUTES: must be positive, got %d", c.Reservations.NoShowGraceMinutes)
+	}
+	if !oneOf(c.Reservations.PersonConflictMode, ConflictModeError, ConflictModeWarn, ConflictModeOff) {
+		add("PERSON_CONFLICT_MODE: must be error, warn or off, got %q", c.Reservations.PersonConflictMode)
+	}
+
+	if c.SMTP.Host != "" {
+		if c.SMTP.Port < 1 || c.SMTP.Port > 65535 {
+			add("SMTP_PORT: must be between 1 and 65535, got %d", c.SMTP.Port)
+		}
+		if c.SMTP.From == "" && c.SMTP.User == "" {
+			add("SMTP_FROM: required when SMTP_HOST is set and SMTP_USER is empty")
+		}
+	}
+
+	if len(c.Reminders.Offsets) == 0 {
+		add("REMINDER_OFFSETS: at least one offset is required")
+	}
+	for _, d := range c.Reminders.Offsets {
+		if d <= 0 {
+			add("REMINDER_OFFSETS: offsets must be positive, got %s", d)
+		}
+	}
+	if c.Reminders.DigestHour < -1 || c.Reminders.DigestHour > 23 {
+		add("DIGEST_HOUR: must be between -1 and 23, got %d", c.Reminders.DigestHour)
+	}
+
+	if c.Webhooks.DisableAfter <= 0 {
+		add("WEBHOOK_DISABLE_AFTER: must be positive, got %d", c.Webhooks.DisableAfter)
+	}
+	if !oneOf(c.Events.Broker, "memory", "none") {
+		add("EVENT_BROKER: must be memory or none, got %q", c.Events.Broker)
+	}
+	for _, f := range c.PublicListing.ReservationFields {
+		if !oneOf(f, PublicReservationFields...) {
+			add("PUBLIC_RESERVATION_FIELDS: unknown field %q (available: %s)", f, strings.Join(PublicReservationFields, ", "))
+		}
+	}
+	return p
+}
+
+func oneOf(v string, options ...string) bool {
+	for _, o := range options {
+		if v == o {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolate deja el test sin variables de configuración heredadas y en un
+// directorio temporal, para que Load no lea el .env del repositorio
+func isolate(t *testing.T) string {
+	t.Helper()
+	visitFields(reflect.ValueOf(&Config{}).Elem(), func(_ reflect.Value, sf reflect.StructField) {
+		if name := sf.Tag.Get("env"); name != "" {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	})
+	t.Setenv("CONFIG_FILE", "")
+	os.Unsetenv("CONFIG_FILE")
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Lo mínimo para que la configuración sea válida
+const requiredEnv = "DB_USER=app\nDB_NAME=app\nJWT_SECRET=0123456789abcdef\n"
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		dotenv   string
+		env      map[string]string
+		wantPort int
+		wantHost string
+	}{
+		{
+			name:     "valores por defecto",
+			wantPort: 8080,
+			wantHost: "localhost",
+		},
+		{
+			name:     "el YAML pisa el valor por defecto",
+			yaml:     "server:\n  port: 9000\ndatabase:\n  host: yaml-db\n",
+			wantPort: 9000,
+			wantHost: "yaml-db",
+		},
+		{
+			name:     ".env pisa al YAML",
+			yaml:     "server:\n  port: 9000\ndatabase:\n  host: yaml-db\n",
+			dotenv:   "PORT=9100\n",
+			wantPort: 9100,
+			wantHost: "yaml-db",
+		},
+		{
+			name:     "el entorno pisa a .env y al YAML",
+			yaml:     "server:\n  port: 9000\ndatabase:\n  host: yaml-db\n",
+			dotenv:   "PORT=9100\nDB_HOST=dotenv-db\n",
+			env:      map[string]string{"PORT": "9200"},
+			wantPort: 9200,
+			wantHost: "dotenv-db",
+		},
+		{
+			name:     "un número vacío en el entorno se ignora",
+			yaml:     "server:\n  port: 9000\n",
+			env:      map[string]string{"PORT": " "},
+			wantPort: 9000,
+			wantHost: "localhost",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := isolate(t)
+			writeFile(t, filepath.Join(dir, ".env"), requiredEnv+tt.dotenv)
+			if tt.yaml != "" {
+				path := filepath.Join(dir, "config.yaml")
+				writeFile(t, path, tt.yaml)
+				t.Setenv("CONFIG_FILE", path)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tt.wantPort {
+				t.Errorf("port = %d, want %d", cfg.Server.Port, tt.wantPort)
+			}
+			if cfg.Database.Host != tt.wantHost {
+				t.Errorf("db host = %q, want %q", cfg.Database.Host, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestLoadParsesTypedValues(t *testing.T) {
+	dir := isolate(t)
+	writeFile(t, filepath.Join(dir, ".env"), requiredEnv)
+	t.Setenv("REMINDER_OFFSETS", "15m, 48h,1h")
+	t.Setenv("CORS_ORIGINS", "https://a.example, https://b.example")
+	t.Setenv("SEED_ALLOW_DEMO", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	wantOffsets := []time.Duration{48 * time.Hour, time.Hour, 15 * time.Minute}
+	if !reflect.DeepEqual(cfg.Reminders.Offsets, wantOffsets) {
+		t.Errorf("offsets = %v, want %v (sorted descending)", cfg.Reminders.Offsets, wantOffsets)
+	}
+	wantOrigins := []string{"https://a.example", "https://b.example"}
+	if !reflect.DeepEqual(cfg.Server.CORSOrigins, wantOrigins) {
+		t.Errorf("cors origins = %v, want %v", cfg.Server.CORSOrigins, wantOrigins)
+	}
+	if !cfg.Seed.AllowDemo {
+		t.Errorf("allow demo = false, want true")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		yaml         string
+		env          map[string]string
+		wantProblems []string // nil: error no recuperable
+		wantErr      string
+	}{
+		{
+			name:         "faltan obligatorios",
+			env:          map[string]string{"DB_USER": "", "JWT_SECRET": "corto"},
+			wantProblems: []string{"DB_USER: required", "JWT_SECRET: required"},
+		},
+		{
+			name:         "valores mal tipados en el entorno",
+			env:          map[string]string{"DB_PORT": "cinco", "SEED_ALLOW_DEMO": "quizás", "REMINDER_OFFSETS": "1d"},
+			wantProblems: []string{`DB_PORT: invalid integer "cinco"`, `SEED_ALLOW_DEMO: invalid boolean "quizás"`, `REMINDER_OFFSETS: invalid duration "1d"`},
+		},
+		{
+			name:         "tipo incorrecto en el YAML",
+			yaml:         "server:\n  port: ochenta\n",
+			wantProblems: []string{"config.yaml: line 2"},
+		},
+		{
+			name:         "clave desconocida en el YAML",
+			yaml:         "server:\n  puerto: 80\n",
+			wantProblems: []string{"field puerto not found"},
+		},
+		{
+			name:    "YAML mal formado",
+			yaml:    "server: [\n",
+			wantErr: "parsing",
+		},
+		{
+			name:    "CONFIG_FILE inexistente",
+			env:     map[string]string{"CONFIG_FILE": "no-existe.yaml"},
+			wantErr: "reading CONFIG_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := isolate(t)
+			writeFile(t, filepath.Join(dir, ".env"), requiredEnv)
+			if tt.yaml != "" {
+				path := filepath.Join(dir, "config.yaml")
+				writeFile(t, path, tt.yaml)
+				t.Setenv("CONFIG_FILE", path)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := Load()
+			if err == nil {
+				t.Fatal("Load succeeded, want error")
+			}
+			var verr *ValidationError
+			if tt.wantProblems == nil {
+				if errors.As(err, &verr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want *ValidationError", err)
+			}
+			if cfg == nil {
+				t.Fatal("config not returned alongside validation error")
+			}
+			for _, want := range tt.wantProblems {
+				if !containsProblem(verr.Problems, want) {
+					t.Errorf("problems %q do not mention %q", verr.Problems, want)
+				}
+			}
+		})
+	}
+}
+
+func containsProblem(problems []string, want string) bool {
+	for _, p := range problems {
+		if strings.Contains(p, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := &Config{}
+	if err := applyDefaults(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Database.User = "app"
+	cfg.Database.Name = "app"
+	cfg.Auth.JWTSecret = "0123456789abcdef"
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		want   string // "" = válida
+	}{
+		{"por defecto", func(c *Config) {}, ""},
+		{"puerto fuera de rango", func(c *Config) { c.Server.Port = 70000 }, "PORT: must be between"},
+		{"origen con ruta", func(c *Config) { c.Server.CORSOrigins = []string{"https://a.example/app"} }, "CORS_ORIGINS:"},
+		{"origen comodín", func(c *Config) { c.Server.CORSOrigins = []string{"*"} }, "CORS_ORIGINS:"},
+		{"sin orígenes", func(c *Config) { c.Server.CORSOrigins = nil }, "at least one origin"},
+		{"sslmode desconocido", func(c *Config) { c.Database.SSLMode = "always" }, "DB_SSLMODE"},
+		{"sólo correo de admin", func(c *Config) { c.Admin.Email = "admin@uni.edu" }, "set both or neither"},
+		{"correo de admin inválido", func(c *Config) { c.Admin.Email, c.Admin.Password = "admin", "x" }, "not an email"},
+		{"admin completo", func(c *Config) { c.Admin.Email, c.Admin.Password = "admin@uni.edu", "x" }, ""},
+		{"gracia no positiva", func(c *Config) { c.Reservations.NoShowGraceMinutes = 0 }, "NO_SHOW_GRACE_MINUTES"},
+		{"modo de conflicto desconocido", func(c *Config) { c.Reservations.PersonConflictMode = "strict" }, "PERSON_CONFLICT_MODE"},
+		{"SMTP sin remitente", func(c *Config) { c.SMTP.Host = "smtp.uni.edu" }, "SMTP_FROM"},
+		{"SMTP con usuario", func(c *Config) { c.SMTP.Host, c.SMTP.User = "smtp.uni.edu", "bot@uni.edu" }, ""},
+		{"SMTP puerto inválido", func(c *Config) { c.SMTP.Host, c.SMTP.From, c.SMTP.Port = "smtp", "a@b", 0 }, "SMTP_PORT"},
+		{"sin recordatorios", func(c *Config) { c.Reminders.Offsets = nil }, "at least one offset"},
+		{"recordatorio negativo", func(c *Config) { c.Reminders.Offsets = []time.Duration{-time.Hour} }, "offsets must be positive"},
+		{"sin resumen diario", func(c *Config) { c.Reminders.DigestHour = -1 }, ""},
+		{"hora de resumen inválida", func(c *Config) { c.Reminders.DigestHour = 24 }, "DIGEST_HOUR"},
+		{"webhooks sin límite", func(c *Config) { c.Webhooks.DisableAfter = 0 }, "WEBHOOK_DISABLE_AFTER"},
+		{"broker desconocido", func(c *Config) { c.Events.Broker = "kafka" }, "EVENT_BROKER"},
+		{"campo público desconocido", func(c *Config) { c.PublicListing.ReservationFields = []string{"user_phone"} }, "unknown field \"user_phone\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.mutate(cfg)
+			problems := cfg.validate()
+			if tt.want == "" {
+				if len(problems) > 0 {
+					t.Errorf("problems = %q, want none", problems)
+				}
+				return
+			}
+			if !containsProblem(problems, tt.want) {
+				t.Errorf("problems = %q, want one mentioning %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Database.Password = "db-pass"
+	cfg.Admin.Email, cfg.Admin.Password = "admin@uni.edu", "admin-pass"
+	cfg.SMTP.User = "bot@uni.edu"
+
+	r := cfg.Redacted()
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"contraseña de la base", r.Database.Password, redacted},
+		{"secreto JWT", r.Auth.JWTSecret, redacted},
+		{"contraseña de admin", r.Admin.Password, redacted},
+		{"secreto vacío se deja vacío", r.SMTP.Password, ""},
+		{"usuario de la base visible", r.Database.User, "app"},
+		{"correo de admin visible", r.Admin.Email, "admin@uni.edu"},
+		{"usuario SMTP visible", r.SMTP.User, "bot@uni.edu"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	if cfg.Database.Password != "db-pass" || cfg.Auth.JWTSecret != "0123456789abcdef" {
+		t.Errorf("Redacted modified the original config")
+	}
+
+	var out strings.Builder
+	if err := cfg.Print(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"db-pass", "admin-pass", "0123456789abcdef"} {
+		if strings.Contains(out.String(), secret) {
+			t.Errorf("Print leaked %q:\n%s", secret, out.String())
+		}
+	}
+}
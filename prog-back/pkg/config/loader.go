@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// visitFields recorre los campos hoja de la configuración (los que no son
+// secciones) con su StructField
+func visitFields(v reflect.Value, fn func(f reflect.Value, sf reflect.StructField)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, sf := v.Field(i), t.Field(i)
+		if f.Kind() == reflect.Struct {
+			visitFields(f, fn)
+			continue
+		}
+		fn(f, sf)
+	}
+}
+
+func applyDefaults(cfg *Config) error {
+	var err error
+	visitFields(reflect.ValueOf(cfg).Elem(), func(f reflect.Value, sf reflect.StructField) {
+		def, ok := sf.Tag.Lookup("default")
+		if !ok || err != nil {
+			return
+		}
+		if e := setField(f, def); e != nil {
+			err = fmt.Errorf("default of %s: %w", sf.Name, e)
+		}
+	})
+	return err
+}
+
+// applyEnv sobrescribe con las variables de entorno definidas. Una variable
+// vacía cuenta como valor en los campos de texto y listas (p. ej. SMTP_HOST=
+// desactiva el correo) y se ignora en números, booleanos y duraciones.
+func applyEnv(cfg *Config) []string {
+	var problems []string
+	visitFields(reflect.ValueOf(cfg).Elem(), func(f reflect.Value, sf reflect.StructField) {
+		name := sf.Tag.Get("env")
+		if name == "" {
+			return
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if strings.TrimSpace(raw) == "" && f.Kind() != reflect.String && f.Kind() != reflect.Slice {
+			return
+		}
+		if err := setField(f, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	})
+	return problems
+}
+
+func setField(f reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.SetBool(b)
+	case reflect.Slice:
+		// Listas separadas por coma; cada elemento con el tipo del slice
+		out := reflect.MakeSlice(f.Type(), 0, 0)
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			elem := reflect.New(f.Type().Elem()).Elem()
+			if err := setField(elem, part); err != nil {
+				return err
+			}
+			out = reflect.Append(out, elem)
+		}
+		f.Set(out)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
+
+const redacted = "[REDACTED]"
+
+// Redacted devuelve una copia con los secretos no vacíos ocultos
+func (c *Config) Redacted() Config {
+	out := *c
+	visitFields(reflect.ValueOf(&out).Elem(), func(f reflect.Value, sf reflect.StructField) {
+		if sf.Tag.Get("secret") == "true" && f.String() != "" {
+			f.SetString(redacted)
+		}
+	})
+	return out
+}
+
+// Print escribe la configuración efectiva en YAML, con los secretos ocultos
+func (c *Config) Print(w io.Writer) error {
+	r := c.Redacted()
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&r); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -1,10 +1,7 @@
 package db
 
 import (
-	"fmt"
-	"os"
-
-	"programcion-backend/internal/models"
+	"programcion-backend/pkg/config"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
@@ -13,44 +10,19 @@ import (
 
 var DB *gorm.DB
 
-func InitDB() error {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	pass := os.Getenv("DB_PASSWORD")
-	name := os.Getenv("DB_NAME")
-	sslmode := os.Getenv("DB_SSLMODE")
-	if sslmode == "" {
-		sslmode = "disable"
-	}
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, pass, name, sslmode)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// InitDB abre la conexión con Postgres
+func InitDB(cfg config.DatabaseConfig) error {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
 	if err != nil {
 		log.WithError(err).Error("No se pudo conectar a la base de datos")
 		return err
 	}
 
-	// Guardar la instancia
+	// Guardar la instancia. El esquema lo gestionan las migraciones versionadas
+	// (ver migrate.go); la conexión no modifica tablas.
 	DB = db
 
-	// Auto-migrate modelos esenciales
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.Building{},
-		&models.Room{},
-		&models.Class{},
-		&models.ClassStudent{},
-		&models.Reservation{},
-	)
-	if err != nil {
-		log.WithError(err).Error("Error en AutoMigrate")
-		return err
-	}
-
-	log.Info("Conexión a la base de datos establecida y migraciones aplicadas")
+	log.Info("Conexión a la base de datos establecida")
 	return nil
 }
 
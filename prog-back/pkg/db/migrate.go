@@ -0,0 +1,340 @@
+package db
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Las migraciones se compilan dentro del binario; MigrationsDir es la ruta en
+// el código fuente donde `migrate create` escribe las nuevas.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+const MigrationsDir = "pkg/db/migrations"
+
+// Clave del advisory lock que serializa las migraciones entre réplicas ("migrate")
+const migrationLockKey = 0x6d696772617465
+
+// Una migración que empieza con esta línea se ejecuta fuera de transacción
+// (p. ej. CREATE INDEX CONCURRENTLY)
+const noTransactionMarker = "-- migrate:no-transaction"
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 del up, para detectar migraciones editadas tras aplicarse
+}
+
+// MigrationStatus describe una migración conocida por el binario o registrada
+// en schema_migrations. Missing indica que está aplicada pero el binario no la
+// conoce (la base viene de una versión más nueva).
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+	Modified  bool       `json:"modified"`
+	Missing   bool       `json:"missing"`
+}
+
+type schemaMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// LoadMigrations lee las migraciones embebidas ordenadas por versión. Cada
+// versión necesita su .up.sql y su .down.sql.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		m := migrationFileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := migrationsFS.ReadFile("migrations/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %04d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+			sum := sha256.Sum256(body)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(body)
+		}
+	}
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// withMigrationLock fija una conexión, toma el advisory lock de sesión y
+// crea schema_migrations si falta. Otra réplica que migre a la vez espera.
+func withMigrationLock(fn func(conn *gorm.DB) error) error {
+	if DB == nil {
+		return errors.New("database not initialized")
+	}
+	return DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+		err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			checksum text NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		)`).Error
+		if err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// appliedMigrations devuelve lo registrado en schema_migrations; si la tabla
+// aún no existe la base no tiene ninguna migración aplicada
+func appliedMigrations(conn *gorm.DB) (map[int]schemaMigration, error) {
+	var exists bool
+	if err := conn.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return nil, err
+	}
+	out := map[int]schemaMigration{}
+	if !exists {
+		return out, nil
+	}
+	var rows []schemaMigration
+	if err := conn.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+func runMigrationSQL(conn *gorm.DB, sql string, record func(tx *gorm.DB) error) error {
+	if strings.HasPrefix(strings.TrimSpace(sql), noTransactionMarker) {
+		if err := conn.Exec(sql).Error; err != nil {
+			return err
+		}
+		return record(conn)
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
+
+// MigrateUp aplica en orden las migraciones pendientes (todas si steps <= 0).
+// Cada una corre en su transacción junto con su registro en schema_migrations.
+func MigrateUp(steps int) ([]Migration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	err = withMigrationLock(func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if steps > 0 && len(done) == steps {
+				break
+			}
+			err := runMigrationSQL(conn, m.Up, func(tx *gorm.DB) error {
+				return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+			log.Infof("Migración aplicada: %04d_%s", m.Version, m.Name)
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrateDown revierte las últimas steps migraciones aplicadas (1 si steps <= 0)
+func MigrateDown(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	known := map[int]Migration{}
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+	var done []Migration
+	err = withMigrationLock(func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+		for _, v := range versions {
+			if len(done) == steps {
+				break
+			}
+			m, ok := known[v]
+			if !ok {
+				return fmt.Errorf("migration %04d_%s is applied but not included in this binary", v, applied[v].Name)
+			}
+			err := runMigrationSQL(conn, m.Down, func(tx *gorm.DB) error {
+				return tx.Where("version = ?", m.Version).Delete(&schemaMigration{}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+			log.Infof("Migración revertida: %04d_%s", m.Version, m.Name)
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrationStatuses combina las migraciones del binario con las aplicadas
+func MigrationStatuses() ([]MigrationStatus, error) {
+	if DB == nil {
+		return nil, errors.New("database not initialized")
+	}
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(DB)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			at := a.AppliedAt
+			st.AppliedAt = &at
+			st.Modified = a.Checksum != m.Checksum
+			delete(applied, m.Version)
+		}
+		out = append(out, st)
+	}
+	for _, a := range applied {
+		at := a.AppliedAt
+		out = append(out, MigrationStatus{Version: a.Version, Name: a.Name, AppliedAt: &at, Missing: true})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// CheckSchema falla si quedan migraciones pendientes. Las migraciones editadas
+// o desconocidas (base más nueva que el binario) sólo generan un aviso, para
+// permitir despliegues escalonados.
+func CheckSchema() error {
+	statuses, err := MigrationStatuses()
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, st := range statuses {
+		switch {
+		case st.Missing:
+			log.Warnf("Migración %04d_%s aplicada pero desconocida para este binario", st.Version, st.Name)
+		case st.AppliedAt == nil:
+			pending = append(pending, fmt.Sprintf("%04d_%s", st.Version, st.Name))
+		case st.Modified:
+			log.Warnf("Migración %04d_%s modificada después de aplicarse", st.Version, st.Name)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %s (run `migrate up`)", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+var migrationNameSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateMigration crea en dir el par up/down vacío con la siguiente versión
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.Trim(migrationNameSanitizer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", errors.New("migration name is required")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	next := 1
+	for _, e := range entries {
+		if m := migrationFileRe.FindStringSubmatch(e.Name()); m != nil {
+			if v, _ := strconv.Atoi(m[1]); v >= next {
+				next = v + 1
+			}
+		}
+	}
+	base := fmt.Sprintf("%04d_%s", next, name)
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+base+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- Revierte "+base+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
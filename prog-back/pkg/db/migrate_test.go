@@ -0,0 +1,38 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %04d_%s: want version %d (no gaps)", m.Version, m.Name, i+1)
+		}
+		if m.Checksum == "" {
+			t.Errorf("migration %04d_%s has no checksum", m.Version, m.Name)
+		}
+	}
+	// La base debe poder adoptar un esquema de AutoMigrate y todo lo posterior
+	// debe ser re-ejecutable sobre él
+	for _, m := range migrations {
+		for _, line := range strings.Split(m.Up, "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "CREATE TABLE ") && !strings.HasPrefix(line, "CREATE TABLE IF NOT EXISTS "),
+				strings.HasPrefix(line, "CREATE INDEX ") && !strings.HasPrefix(line, "CREATE INDEX IF NOT EXISTS "),
+				strings.HasPrefix(line, "CREATE UNIQUE INDEX ") && !strings.HasPrefix(line, "CREATE UNIQUE INDEX IF NOT EXISTS "),
+				strings.Contains(line, "ADD COLUMN ") && !strings.Contains(line, "ADD COLUMN IF NOT EXISTS "):
+				t.Errorf("migration %04d_%s is not idempotent: %s", m.Version, m.Name, line)
+			}
+		}
+	}
+}
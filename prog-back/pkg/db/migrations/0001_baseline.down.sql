@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "reservations";
+DROP TABLE IF EXISTS "class_students";
+DROP TABLE IF EXISTS "classes";
+DROP TABLE IF EXISTS "rooms";
+DROP TABLE IF EXISTS "buildings";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,87 @@
+-- Esquema inicial: exactamente lo que generaba AutoMigrate antes de las
+-- migraciones versionadas (users, buildings, rooms, classes, class_students y
+-- reservations). Usa IF NOT EXISTS para que esas bases la adopten sin cambios;
+-- lo agregado después llega con las migraciones siguientes.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "email" text NOT NULL,
+    "password_hash" text NOT NULL,
+    "role" text NOT NULL,
+    "is_confirmed" boolean DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+CREATE TABLE IF NOT EXISTS "buildings" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "address" text,
+    "campus" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "rooms" (
+    "id" bigserial,
+    "building_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "capacity" bigint NOT NULL,
+    "resources" text,
+    "description" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_rooms_building" FOREIGN KEY ("building_id") REFERENCES "buildings"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_rooms_building_id" ON "rooms" ("building_id");
+
+CREATE TABLE IF NOT EXISTS "classes" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "description" text,
+    "subject" text,
+    "professor_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_classes_professor" FOREIGN KEY ("professor_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_classes_deleted_at" ON "classes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "class_students" (
+    "class_id" bigint,
+    "student_id" bigint,
+    "enrolled_at" timestamptz,
+    PRIMARY KEY ("class_id","student_id")
+);
+
+CREATE TABLE IF NOT EXISTS "reservations" (
+    "id" bigserial,
+    "room_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "class_id" bigint,
+    "start_time" timestamptz NOT NULL,
+    "end_time" timestamptz NOT NULL,
+    "purpose" text,
+    "estimated_attendees" bigint,
+    "status" text NOT NULL DEFAULT 'ACTIVE',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_reservations_class" FOREIGN KEY ("class_id") REFERENCES "classes"("id"),
+    CONSTRAINT "fk_reservations_room" FOREIGN KEY ("room_id") REFERENCES "rooms"("id"),
+    CONSTRAINT "fk_reservations_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reservations_end_time" ON "reservations" ("end_time");
+CREATE INDEX IF NOT EXISTS "idx_reservations_start_time" ON "reservations" ("start_time");
+CREATE INDEX IF NOT EXISTS "idx_reservations_class_id" ON "reservations" ("class_id");
+CREATE INDEX IF NOT EXISTS "idx_reservations_user_id" ON "reservations" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_reservations_room_id" ON "reservations" ("room_id");
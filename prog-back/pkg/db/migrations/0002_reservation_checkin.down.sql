@@ -0,0 +1,2 @@
+ALTER TABLE "reservations" DROP COLUMN IF EXISTS "checked_in_at";
+ALTER TABLE "rooms" DROP COLUMN IF EXISTS "checkin_token";
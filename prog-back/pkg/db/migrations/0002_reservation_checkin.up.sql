@@ -0,0 +1,9 @@
+-- Check-in de reservas con el QR de la puerta del aula. Las aulas existentes
+-- reciben un token al azar (32 caracteres hex, como RegenerateCheckinToken).
+
+ALTER TABLE "rooms" ADD COLUMN IF NOT EXISTS "checkin_token" text;
+UPDATE "rooms" SET "checkin_token" = replace(gen_random_uuid()::text, '-', '')
+WHERE "checkin_token" IS NULL OR "checkin_token" = '';
+CREATE INDEX IF NOT EXISTS "idx_rooms_checkin_token" ON "rooms" ("checkin_token");
+
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "checked_in_at" timestamptz;
@@ -0,0 +1,3 @@
+-- Bloqueos temporales (HELD) que vencen solos
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "hold_expires_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_reservations_hold_expires_at" ON "reservations" ("hold_expires_at");
@@ -0,0 +1,5 @@
+ALTER TABLE "reservations" DROP COLUMN IF EXISTS "term_id";
+ALTER TABLE "classes" DROP COLUMN IF EXISTS "archived_at";
+ALTER TABLE "classes" DROP COLUMN IF EXISTS "term_id";
+DROP TABLE IF EXISTS "term_holidays";
+DROP TABLE IF EXISTS "terms";
@@ -0,0 +1,34 @@
+-- Períodos académicos con feriados. Las clases y reservas existentes quedan
+-- sin período (term_id NULL), como las creadas sin él.
+
+CREATE TABLE IF NOT EXISTS "terms" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "start_date" date NOT NULL,
+    "end_date" date NOT NULL,
+    "enrollment_start" timestamptz,
+    "enrollment_end" timestamptz,
+    "archived_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "term_holidays" (
+    "id" bigserial,
+    "term_id" bigint NOT NULL,
+    "date" date NOT NULL,
+    "name" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_terms_holidays" FOREIGN KEY ("term_id") REFERENCES "terms"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_term_holidays_term_id" ON "term_holidays" ("term_id");
+
+ALTER TABLE "classes" ADD COLUMN IF NOT EXISTS "term_id" bigint;
+ALTER TABLE "classes" ADD COLUMN IF NOT EXISTS "archived_at" timestamptz;
+ALTER TABLE "classes" DROP CONSTRAINT IF EXISTS "fk_classes_term";
+ALTER TABLE "classes" ADD CONSTRAINT "fk_classes_term" FOREIGN KEY ("term_id") REFERENCES "terms"("id");
+CREATE INDEX IF NOT EXISTS "idx_classes_term_id" ON "classes" ("term_id");
+
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "term_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_reservations_term_id" ON "reservations" ("term_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "reservations" DROP COLUMN IF EXISTS "session_id";
+DROP TABLE IF EXISTS "class_sessions";
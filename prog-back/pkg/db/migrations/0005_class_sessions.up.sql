@@ -0,0 +1,18 @@
+-- Horario semanal de las clases; cada sesión genera reservas enlazadas
+CREATE TABLE IF NOT EXISTS "class_sessions" (
+    "id" bigserial,
+    "class_id" bigint NOT NULL,
+    "room_id" bigint NOT NULL,
+    "weekday" bigint NOT NULL,
+    "start_time" text NOT NULL,
+    "end_time" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_class_sessions_room" FOREIGN KEY ("room_id") REFERENCES "rooms"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_class_sessions_room_id" ON "class_sessions" ("room_id");
+CREATE INDEX IF NOT EXISTS "idx_class_sessions_class_id" ON "class_sessions" ("class_id");
+
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "session_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_reservations_session_id" ON "reservations" ("session_id");
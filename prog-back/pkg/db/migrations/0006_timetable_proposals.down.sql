@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "timetable_assignments";
+DROP TABLE IF EXISTS "timetable_proposals";
@@ -0,0 +1,27 @@
+-- Propuestas de horario del planificador
+CREATE TABLE IF NOT EXISTS "timetable_proposals" (
+    "id" bigserial,
+    "term_id" bigint NOT NULL,
+    "created_by_id" bigint NOT NULL,
+    "status" text NOT NULL DEFAULT 'DRAFT',
+    "score" decimal,
+    "unassigned" jsonb,
+    "committed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_timetable_proposals_term_id" ON "timetable_proposals" ("term_id");
+
+CREATE TABLE IF NOT EXISTS "timetable_assignments" (
+    "id" bigserial,
+    "proposal_id" bigint NOT NULL,
+    "class_id" bigint NOT NULL,
+    "room_id" bigint NOT NULL,
+    "weekday" bigint NOT NULL,
+    "start_time" text NOT NULL,
+    "end_time" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_timetable_proposals_assignments" FOREIGN KEY ("proposal_id") REFERENCES "timetable_proposals"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_timetable_assignments_proposal_id" ON "timetable_assignments" ("proposal_id");
@@ -0,0 +1,4 @@
+ALTER TABLE "class_students" DROP COLUMN IF EXISTS "status";
+ALTER TABLE "classes" DROP COLUMN IF EXISTS "join_requires_approval";
+ALTER TABLE "classes" DROP COLUMN IF EXISTS "join_enabled";
+ALTER TABLE "classes" DROP COLUMN IF EXISTS "join_code";
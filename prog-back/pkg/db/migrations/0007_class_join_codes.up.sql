@@ -0,0 +1,9 @@
+-- Auto-inscripción con código y solicitudes pendientes de aprobación. Las
+-- inscripciones existentes quedan ENROLLED (valor por defecto de la columna).
+
+ALTER TABLE "classes" ADD COLUMN IF NOT EXISTS "join_code" text;
+ALTER TABLE "classes" ADD COLUMN IF NOT EXISTS "join_enabled" boolean DEFAULT false;
+ALTER TABLE "classes" ADD COLUMN IF NOT EXISTS "join_requires_approval" boolean DEFAULT false;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_classes_join_code" ON "classes" ("join_code");
+
+ALTER TABLE "class_students" ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT 'ENROLLED';
@@ -0,0 +1 @@
+ALTER TABLE "classes" DROP COLUMN IF EXISTS "max_students";
@@ -0,0 +1,2 @@
+-- Cupo de las clases (0 = sin límite, así quedan las existentes)
+ALTER TABLE "classes" ADD COLUMN IF NOT EXISTS "max_students" bigint DEFAULT 0;
@@ -0,0 +1,15 @@
+-- Asistencia por sesión
+CREATE TABLE IF NOT EXISTS "attendances" (
+    "id" bigserial,
+    "reservation_id" bigint NOT NULL,
+    "student_id" bigint NOT NULL,
+    "status" text NOT NULL,
+    "marked_by_id" bigint,
+    "note" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_attendances_student" FOREIGN KEY ("student_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_attendances_student_id" ON "attendances" ("student_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_attendance_resv_student" ON "attendances" ("reservation_id","student_id");
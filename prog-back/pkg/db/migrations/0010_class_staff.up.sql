@@ -0,0 +1,11 @@
+-- Equipo docente de las clases. El profesor titular (classes.professor_id)
+-- es OWNER implícito, así que no hace falta copiarlo.
+CREATE TABLE IF NOT EXISTS "class_staffs" (
+    "class_id" bigint,
+    "user_id" bigint,
+    "role" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("class_id","user_id"),
+    CONSTRAINT "fk_class_staffs_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_classes_staff" FOREIGN KEY ("class_id") REFERENCES "classes"("id")
+);
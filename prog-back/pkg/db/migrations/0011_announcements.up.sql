@@ -0,0 +1,21 @@
+-- Avisos de clase con estado de lectura por estudiante
+CREATE TABLE IF NOT EXISTS "announcements" (
+    "id" bigserial,
+    "class_id" bigint NOT NULL,
+    "author_id" bigint,
+    "reservation_id" bigint,
+    "title" text NOT NULL,
+    "body" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_announcements_author" FOREIGN KEY ("author_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_announcements_class_id" ON "announcements" ("class_id");
+
+CREATE TABLE IF NOT EXISTS "announcement_reads" (
+    "announcement_id" bigint,
+    "user_id" bigint,
+    "read_at" timestamptz,
+    PRIMARY KEY ("announcement_id","user_id")
+);
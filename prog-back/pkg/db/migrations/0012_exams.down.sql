@@ -0,0 +1,7 @@
+ALTER TABLE "reservations" DROP COLUMN IF EXISTS "kind";
+ALTER TABLE "reservations" DROP COLUMN IF EXISTS "exam_id";
+ALTER TABLE "rooms" DROP COLUMN IF EXISTS "exam_capacity";
+DROP TABLE IF EXISTS "exam_seats";
+DROP TABLE IF EXISTS "exam_invigilators";
+DROP TABLE IF EXISTS "exam_rooms";
+DROP TABLE IF EXISTS "exams";
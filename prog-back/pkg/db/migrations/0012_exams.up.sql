@@ -0,0 +1,57 @@
+-- Exámenes en varias aulas con vigilantes y asientos. Las reservas
+-- existentes son REGULAR y las aulas toman la mitad de su capacidad en modo
+-- examen (exam_capacity 0).
+
+CREATE TABLE IF NOT EXISTS "exams" (
+    "id" bigserial,
+    "class_id" bigint NOT NULL,
+    "title" text NOT NULL,
+    "start_time" timestamptz NOT NULL,
+    "end_time" timestamptz NOT NULL,
+    "status" text NOT NULL DEFAULT 'ACTIVE',
+    "created_by_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_exams_class" FOREIGN KEY ("class_id") REFERENCES "classes"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_exams_end_time" ON "exams" ("end_time");
+CREATE INDEX IF NOT EXISTS "idx_exams_start_time" ON "exams" ("start_time");
+CREATE INDEX IF NOT EXISTS "idx_exams_class_id" ON "exams" ("class_id");
+
+CREATE TABLE IF NOT EXISTS "exam_rooms" (
+    "id" bigserial,
+    "exam_id" bigint NOT NULL,
+    "room_id" bigint NOT NULL,
+    "reservation_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_exam_rooms_room" FOREIGN KEY ("room_id") REFERENCES "rooms"("id"),
+    CONSTRAINT "fk_exams_rooms" FOREIGN KEY ("exam_id") REFERENCES "exams"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_exam_rooms_room_id" ON "exam_rooms" ("room_id");
+CREATE INDEX IF NOT EXISTS "idx_exam_rooms_exam_id" ON "exam_rooms" ("exam_id");
+
+CREATE TABLE IF NOT EXISTS "exam_invigilators" (
+    "exam_room_id" bigint,
+    "user_id" bigint,
+    "exam_id" bigint NOT NULL,
+    PRIMARY KEY ("exam_room_id","user_id"),
+    CONSTRAINT "fk_exam_invigilators_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_exam_rooms_invigilators" FOREIGN KEY ("exam_room_id") REFERENCES "exam_rooms"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_exam_invigilators_exam_id" ON "exam_invigilators" ("exam_id");
+
+CREATE TABLE IF NOT EXISTS "exam_seats" (
+    "exam_room_id" bigint,
+    "student_id" bigint,
+    "seat_number" bigint NOT NULL,
+    PRIMARY KEY ("exam_room_id","student_id"),
+    CONSTRAINT "fk_exam_seats_student" FOREIGN KEY ("student_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_exam_rooms_seats" FOREIGN KEY ("exam_room_id") REFERENCES "exam_rooms"("id")
+);
+
+ALTER TABLE "rooms" ADD COLUMN IF NOT EXISTS "exam_capacity" bigint DEFAULT 0;
+
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "exam_id" bigint;
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "kind" text NOT NULL DEFAULT 'REGULAR';
+CREATE INDEX IF NOT EXISTS "idx_reservations_exam_id" ON "reservations" ("exam_id");
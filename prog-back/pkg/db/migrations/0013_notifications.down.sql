@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "notification_preferences";
+DROP TABLE IF EXISTS "notification_deliveries";
+DROP TABLE IF EXISTS "notifications";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "locale";
@@ -0,0 +1,43 @@
+-- Notificaciones in-app y por correo, con preferencias por evento e idioma
+-- por usuario (los existentes quedan en español)
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "locale" text NOT NULL DEFAULT 'es';
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "event" text NOT NULL,
+    "title" text NOT NULL,
+    "body" text,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_id" ON "notifications" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "notification_deliveries" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "event" text NOT NULL,
+    "channel" text NOT NULL,
+    "subject" text,
+    "body" text,
+    "status" text NOT NULL DEFAULT 'PENDING',
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    "last_error" text,
+    "sent_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notification_deliveries_next_attempt_at" ON "notification_deliveries" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_notification_deliveries_status" ON "notification_deliveries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_notification_deliveries_user_id" ON "notification_deliveries" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "user_id" bigint,
+    "event" text,
+    "email" boolean NOT NULL,
+    "in_app" boolean NOT NULL,
+    PRIMARY KEY ("user_id","event")
+);
@@ -0,0 +1,6 @@
+-- Claves de recordatorios y resúmenes ya enviados (deduplicación entre réplicas)
+CREATE TABLE IF NOT EXISTS "reminder_claims" (
+    "key" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("key")
+);
@@ -0,0 +1,16 @@
+-- Outbox transaccional de eventos de dominio
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+    "id" bigserial,
+    "aggregate_type" text NOT NULL,
+    "aggregate_id" bigint NOT NULL,
+    "event" text NOT NULL,
+    "payload" jsonb NOT NULL,
+    "created_at" timestamptz,
+    "published_at" timestamptz,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "last_error" text,
+    "next_attempt_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_published_at" ON "outbox_events" ("published_at");
+CREATE INDEX IF NOT EXISTS "idx_outbox_aggregate" ON "outbox_events" ("aggregate_type","aggregate_id");
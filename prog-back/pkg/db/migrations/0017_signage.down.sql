@@ -0,0 +1,2 @@
+ALTER TABLE "rooms" DROP COLUMN IF EXISTS "instant_booking";
+DROP TABLE IF EXISTS "signage_tokens";
@@ -0,0 +1,20 @@
+-- Pantallas de cartelería con token y reserva instantánea (desactivada en
+-- las aulas existentes)
+
+CREATE TABLE IF NOT EXISTS "signage_tokens" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "room_id" bigint,
+    "building_id" bigint,
+    "created_by_id" bigint,
+    "last_used_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_signage_tokens_building_id" ON "signage_tokens" ("building_id");
+CREATE INDEX IF NOT EXISTS "idx_signage_tokens_room_id" ON "signage_tokens" ("room_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_signage_tokens_token_hash" ON "signage_tokens" ("token_hash");
+
+ALTER TABLE "rooms" ADD COLUMN IF NOT EXISTS "instant_booking" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+-- Reservas privadas: ocultan motivo y clase en las vistas públicas
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "private" boolean NOT NULL DEFAULT false;
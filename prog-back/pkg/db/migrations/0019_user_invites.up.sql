@@ -0,0 +1,14 @@
+-- Invitaciones de cuentas creadas desde un padrón: el invitado elige su
+-- contraseña con el token que recibe por correo.
+
+CREATE TABLE IF NOT EXISTS "user_invites" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_hash" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_invites_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_invites_user_id" ON "user_invites" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_invites_token_hash" ON "user_invites" ("token_hash");
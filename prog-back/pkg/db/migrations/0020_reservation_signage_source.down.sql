@@ -0,0 +1 @@
+ALTER TABLE "reservations" DROP COLUMN IF EXISTS "signage_token_id";
@@ -0,0 +1,9 @@
+-- Origen de las reservas instantáneas: el token de la pantalla que las hizo.
+-- Siguen a nombre del admin que creó el token, pero no le pertenecen como
+-- persona (sin recordatorios, resúmenes ni choques de agenda).
+
+ALTER TABLE "reservations" ADD COLUMN IF NOT EXISTS "signage_token_id" bigint;
+ALTER TABLE "reservations" DROP CONSTRAINT IF EXISTS "fk_reservations_signage_token";
+ALTER TABLE "reservations" ADD CONSTRAINT "fk_reservations_signage_token"
+    FOREIGN KEY ("signage_token_id") REFERENCES "signage_tokens"("id");
+CREATE INDEX IF NOT EXISTS "idx_reservations_signage_token_id" ON "reservations" ("signage_token_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "user_invites" DROP COLUMN IF EXISTS "accepted_at";
+ALTER TABLE "user_invites" DROP COLUMN IF EXISTS "class_id";
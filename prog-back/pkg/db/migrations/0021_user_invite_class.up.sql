@@ -0,0 +1,10 @@
+-- Inscripción pendiente de los invitados desde un padrón: la clase queda en
+-- la invitación hasta que la cuenta se confirma. Aceptar la invitación ya no
+-- la borra si todavía falta inscribir al invitado.
+
+ALTER TABLE "user_invites" ADD COLUMN IF NOT EXISTS "class_id" bigint;
+ALTER TABLE "user_invites" ADD COLUMN IF NOT EXISTS "accepted_at" timestamptz;
+ALTER TABLE "user_invites" DROP CONSTRAINT IF EXISTS "fk_user_invites_class";
+ALTER TABLE "user_invites" ADD CONSTRAINT "fk_user_invites_class"
+    FOREIGN KEY ("class_id") REFERENCES "classes"("id") ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS "idx_user_invites_class_id" ON "user_invites" ("class_id");
@@ -0,0 +1,5 @@
+-- Las filas sin autor quedan con 0 para poder volver a exigir la columna
+UPDATE "timetable_proposals" SET "created_by_id" = 0 WHERE "created_by_id" IS NULL;
+ALTER TABLE "timetable_proposals" ALTER COLUMN "created_by_id" SET NOT NULL;
+UPDATE "exams" SET "created_by_id" = 0 WHERE "created_by_id" IS NULL;
+ALTER TABLE "exams" ALTER COLUMN "created_by_id" SET NOT NULL;
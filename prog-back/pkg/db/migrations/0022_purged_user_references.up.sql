@@ -0,0 +1,5 @@
+-- Al purgar un usuario sus exámenes, propuestas, webhooks, tokens revocados y
+-- registros de asistencia se conservan sin autor.
+
+ALTER TABLE "exams" ALTER COLUMN "created_by_id" DROP NOT NULL;
+ALTER TABLE "timetable_proposals" ALTER COLUMN "created_by_id" DROP NOT NULL;
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICSEvent es un evento de calendario iCalendar (RFC 5545)
+type ICSEvent struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+}
+
+const icsTimeFormat = "20060102T150405Z"
+
+// Los saltos de línea (LF o CRLF) se escriben como \n; un CR suelto se descarta
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+// Largo máximo de una línea de contenido, en octetos y sin contar el CRLF
+const icsLineLimit = 75
+
+// WriteICS escribe un calendario con los eventos indicados
+func WriteICS(w io.Writer, name string, events []ICSEvent) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//programcion-backend//ES",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + icsEscaper.Replace(name),
+	}
+	stamp := time.Now().UTC().Format(icsTimeFormat)
+	for _, e := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+e.UID,
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+e.Start.UTC().Format(icsTimeFormat),
+			"DTEND:"+e.End.UTC().Format(icsTimeFormat),
+			"SUMMARY:"+icsEscaper.Replace(e.Summary),
+		)
+		if e.Location != "" {
+			lines = append(lines, "LOCATION:"+icsEscaper.Replace(e.Location))
+		}
+		if e.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+icsEscaper.Replace(e.Description))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	for _, l := range lines {
+		if _, err := fmt.Fprint(w, foldICSLine(l), "\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldICSLine parte la línea en tramos de hasta 75 octetos unidos por CRLF y
+// un espacio (RFC 5545 §3.1), sin cortar un carácter UTF-8 multibyte
+func foldICSLine(line string) string {
+	if len(line) <= icsLineLimit {
+		return line
+	}
+	var b strings.Builder
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// El espacio inicial cuenta dentro de los 75 octetos
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
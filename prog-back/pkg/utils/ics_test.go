@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"corta", "SUMMARY:Clase"},
+		{"exactamente 75 octetos", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"ASCII larga", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multibyte en el borde", "SUMMARY:" + strings.Repeat("a", 66) + strings.Repeat("ñ", 40)},
+		{"emojis", "DESCRIPTION:" + strings.Repeat("📚", 60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := foldICSLine(tt.line)
+			parts := strings.Split(folded, "\r\n")
+			for i, p := range parts {
+				if len(p) > icsLineLimit {
+					t.Errorf("line %d has %d octets, want <= %d", i, len(p), icsLineLimit)
+				}
+				if i > 0 && !strings.HasPrefix(p, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, p)
+				}
+				if !utf8.ValidString(p) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, p)
+				}
+			}
+			if got := strings.ReplaceAll(folded, "\r\n ", ""); got != tt.line {
+				t.Errorf("unfolded = %q, want %q", got, tt.line)
+			}
+			if len(tt.line) <= icsLineLimit && folded != tt.line {
+				t.Errorf("short line was folded: %q", folded)
+			}
+		})
+	}
+}
+
+func TestWriteICSEscapesText(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	err := WriteICS(&buf, "Horario", []ICSEvent{{
+		UID:         "1@test",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Summary:     "Álgebra; parcial, aula 3",
+		Description: "línea 1\r\nlínea 2\rfin\núltima",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"SUMMARY:Álgebra\\; parcial\\, aula 3\r\n",
+		"DESCRIPTION:línea 1\\nlínea 2fin\\núltima\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	// Todo CR del contenido forma parte de un CRLF
+	if strings.Count(out, "\r") != strings.Count(out, "\r\n") {
+		t.Errorf("stray CR in output: %q", out)
+	}
+}